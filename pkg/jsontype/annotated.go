@@ -0,0 +1,263 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/ctx42/convert/pkg/convert"
+)
+
+// TypesKey is the key of the object with type annotations in documents
+// encoded by [MarshalAnnotated].
+const TypesKey = "$types"
+
+// Annotated represents a JSON object with values of any type. Unlike [Map],
+// it is marshaled as a plain JSON document with an additional [TypesKey] key
+// holding an object which maps JSON Pointers (RFC 6901) of values which are
+// not native JSON types to their type names. Consumers unaware of the
+// annotations see the document in its usual shape.
+//
+// Example:
+//
+//	{
+//	  "id": 42,
+//	  "tags": ["a", "1s"],
+//	  "$types": {"/id": "uint64", "/tags/1": "time.Duration"}
+//	}
+type Annotated map[string]any
+
+// MarshalJSON uses the package-level registry. To marshal with a custom
+// registry, call [MarshalAnnotated] directly.
+func (a Annotated) MarshalJSON() ([]byte, error) {
+	return MarshalAnnotated(registry, a)
+}
+
+// UnmarshalJSON uses the package-level registry. To unmarshal with a custom
+// registry, call [UnmarshalAnnotated] directly.
+func (a *Annotated) UnmarshalJSON(bytes []byte) error {
+	return UnmarshalAnnotated(registry, bytes, a)
+}
+
+// MarshalAnnotated marshals the document using encoders from [Registry]. The
+// type annotations are added only when there is at least one value which is
+// not a native JSON type. Returns an error when the document already has the
+// [TypesKey] key.
+func MarshalAnnotated(reg *Registry, a Annotated) ([]byte, error) {
+	if a == nil {
+		return []byte("null"), nil
+	}
+	if _, ok := a[TypesKey]; ok {
+		format := "jsontype: %w: document has the %q key"
+		return nil, fmt.Errorf(format, convert.ErrInvFormat, TypesKey)
+	}
+	types := make(map[string]string)
+	v, err := annotateTree(reg, "", map[string]any(a), types)
+	if err != nil {
+		return nil, fmt.Errorf("jsontype: %w", err)
+	}
+	doc := v.(map[string]any) // nolint: forcetypeassert
+	if len(types) > 0 {
+		doc[TypesKey] = types
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalAnnotated unmarshals JSON object to the [Annotated] document,
+// values at paths listed in the [TypesKey] object are converted with
+// converters from the [Registry]. Numbers at other paths are decoded as
+// float64. Returns an error when the annotated path does not exist or its
+// type name is not supported. Use [WithAllowedTypes] or [WithTypeFilter] to
+// limit the accepted types, the other options are ignored.
+func UnmarshalAnnotated(
+	reg *Registry,
+	bytes []byte,
+	a *Annotated,
+	opts ...Option,
+) error {
+
+	def := newOptions(opts...)
+	def.reg = reg
+	v, err := decodeNumber(bytes)
+	if err != nil {
+		return fmt.Errorf("jsontype: %w", err)
+	}
+	if v == nil {
+		*a = nil
+		return nil
+	}
+	doc, ok := v.(map[string]any)
+	if !ok {
+		return fmt.Errorf("jsontype: %w", invTypeError(typAnyMap, v))
+	}
+	types, err := annotations(doc[TypesKey])
+	if err != nil {
+		return fmt.Errorf("jsontype: %s: %w", TypesKey, err)
+	}
+	delete(doc, TypesKey)
+	if _, err = restoreTree(def, "", doc, types); err != nil {
+		return fmt.Errorf("jsontype: %w", err)
+	}
+	if len(types) > 0 {
+		ptr := slices.Min(slices.Collect(maps.Keys(types)))
+		format := "jsontype: path %q: %w: path not found"
+		return fmt.Errorf(format, ptr, convert.ErrInvFormat)
+	}
+	*a = doc
+	return nil
+}
+
+// annotations returns type annotations from the decoded [TypesKey] value.
+func annotations(v any) (map[string]string, error) {
+	types := make(map[string]string)
+	if v == nil {
+		return types, nil
+	}
+	src, ok := v.(map[string]any)
+	if !ok {
+		return nil, invTypeError(typAnyMap, v)
+	}
+	for ptr, elem := range src {
+		typ, ok := elem.(string)
+		if !ok {
+			err := invTypeError(typString, elem)
+			return nil, fmt.Errorf("path %q: %w", ptr, err)
+		}
+		types[ptr] = typ
+	}
+	return types, nil
+}
+
+// annotateTree encodes the value at the given JSON Pointer for [json.Marshal].
+// Maps and slices are walked, native JSON values are returned as they are.
+// All other values are encoded with their encoders and their type names are
+// added to the types map.
+func annotateTree(
+	reg *Registry,
+	ptr string,
+	v any,
+	types map[string]string,
+) (any, error) {
+
+	switch val := v.(type) {
+	case nil, string, bool, float64, json.Number:
+		return val, nil
+
+	case Map:
+		return annotateTree(reg, ptr, map[string]any(val), types)
+
+	case map[string]any:
+		dst := make(map[string]any, len(val))
+		for key, elem := range val {
+			ev, err := annotateTree(reg, ptr+"/"+escapeToken(key), elem, types)
+			if err != nil {
+				return nil, err
+			}
+			dst[key] = ev
+		}
+		return dst, nil
+
+	case List:
+		return annotateTree(reg, ptr, []any(val), types)
+
+	case []any:
+		dst := make([]any, len(val))
+		for i, elem := range val {
+			var err error
+			pth := ptr + "/" + strconv.Itoa(i)
+			if dst[i], err = annotateTree(reg, pth, elem, types); err != nil {
+				return nil, err
+			}
+		}
+		return dst, nil
+
+	case *Value:
+		ev, err := val.encode(reg)
+		if err != nil {
+			return nil, fmt.Errorf("path %q: %w", ptr, err)
+		}
+		types[ptr] = val.typ
+		return ev, nil
+
+	default:
+		rt := reflect.TypeOf(v)
+		typ := reg.typeName(rt)
+		if typ == "" {
+			format := "path %q: %w: %s"
+			return nil, fmt.Errorf(format, ptr, convert.ErrUnsType, rt)
+		}
+		return annotateTree(reg, ptr, &Value{typ: typ, val: v}, types)
+	}
+}
+
+// restoreTree decodes the value at the given JSON Pointer. Values at paths
+// present in the types map are converted with registered converters, and the
+// paths are removed from the map. Other maps and slices are walked and
+// numbers are converted to float64. The walk does not stop on the first
+// conversion error, errors for all paths are joined in path order. Type names
+// rejected by the type filter are errors too.
+func restoreTree(
+	def *Options,
+	ptr string,
+	v any,
+	types map[string]string,
+) (any, error) {
+
+	if typ, ok := types[ptr]; ok {
+		delete(types, ptr)
+		if err := def.allowed(typ); err != nil {
+			return nil, fmt.Errorf("path %q: %w", ptr, err)
+		}
+		cnv := def.reg.decoder(typ)
+		if cnv == nil {
+			format := "path %q: %w: %s"
+			return nil, fmt.Errorf(format, ptr, convert.ErrUnsType, typ)
+		}
+		ret, err := cnv(v)
+		if err != nil {
+			return nil, fmt.Errorf("path %q: %w", ptr, err)
+		}
+		return ret, nil
+	}
+
+	switch val := v.(type) {
+	case json.Number:
+		return parseFloat(val)
+
+	case map[string]any:
+		var errs []error
+		for _, key := range slices.Sorted(maps.Keys(val)) {
+			pth := ptr + "/" + escapeToken(key)
+			ev, err := restoreTree(def, pth, val[key], types)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			val[key] = ev
+		}
+		return val, errors.Join(errs...)
+
+	case []any:
+		var errs []error
+		for i, elem := range val {
+			pth := ptr + "/" + strconv.Itoa(i)
+			ev, err := restoreTree(def, pth, elem, types)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			val[i] = ev
+		}
+		return val, errors.Join(errs...)
+
+	default:
+		return val, nil
+	}
+}
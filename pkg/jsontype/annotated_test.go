@@ -0,0 +1,310 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ctx42/convert/pkg/convert"
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
+)
+
+func Test_Annotated_MarshalJSON(t *testing.T) {
+	t.Run("nested", func(t *testing.T) {
+		// --- Given ---
+		a := Annotated{
+			"nil":  nil,
+			"str":  "abc",
+			"u64":  uint64(1<<64 - 1),
+			"list": List{true, time.Second},
+			"map":  map[string]any{"a/b~c": int8(-8)},
+			"val":  New([]uint{1, 2}),
+		}
+
+		// --- When ---
+		have, err := json.Marshal(a)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := `{
+			"list": [true, "1s"],
+			"map": {"a/b~c": -8},
+			"nil": null,
+			"str": "abc",
+			"u64": 18446744073709551615,
+			"val": [1, 2],
+			"$types": {
+				"/list/1": "time.Duration",
+				"/map/a~1b~0c": "int8",
+				"/u64": "uint64",
+				"/val": "[]uint"
+			}
+		}`
+		assert.JSON(t, want, string(have))
+	})
+
+	t.Run("native types only", func(t *testing.T) {
+		// --- Given ---
+		a := Annotated{"a": 1.5, "b": []any{"c"}}
+
+		// --- When ---
+		have, err := json.Marshal(a)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.JSON(t, `{"a": 1.5, "b": ["c"]}`, string(have))
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		// --- Given ---
+		var a Annotated
+
+		// --- When ---
+		have, err := json.Marshal(a)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "null", string(have))
+	})
+
+	t.Run("error - types key", func(t *testing.T) {
+		// --- Given ---
+		a := Annotated{"$types": nil}
+
+		// --- When ---
+		have, err := json.Marshal(a)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvFormat, err)
+		wMsg := "jsontype: invalid format: document has the \"$types\" key"
+		assert.ErrorContain(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - unsupported type", func(t *testing.T) {
+		// --- Given ---
+		a := Annotated{"a": []any{struct{}{}}}
+
+		// --- When ---
+		have, err := json.Marshal(a)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		wMsg := "jsontype: path \"/a/0\": unsupported type: struct {}"
+		assert.ErrorContain(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_Annotated_UnmarshalJSON(t *testing.T) {
+	t.Run("nested", func(t *testing.T) {
+		// --- Given ---
+		data := `{
+			"f64": 1,
+			"list": [true, "1s"],
+			"map": {"a/b~c": -8},
+			"nil": null,
+			"u64": 18446744073709551615,
+			"val": [1, 2],
+			"$types": {
+				"/list/1": "time.Duration",
+				"/map/a~1b~0c": "int8",
+				"/u64": "uint64",
+				"/val": "[]uint"
+			}
+		}`
+
+		// --- When ---
+		var have Annotated
+		err := json.Unmarshal([]byte(data), &have)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := Annotated{
+			"f64":  1.0,
+			"list": []any{true, time.Second},
+			"map":  map[string]any{"a/b~c": int8(-8)},
+			"nil":  nil,
+			"u64":  uint64(1<<64 - 1),
+			"val":  []uint{1, 2},
+		}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("without annotations", func(t *testing.T) {
+		// --- When ---
+		var have Annotated
+		err := json.Unmarshal([]byte(`{"a": 1}`), &have)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, Annotated{"a": 1.0}, have)
+	})
+
+	t.Run("null", func(t *testing.T) {
+		// --- Given ---
+		have := Annotated{"a": 1.0}
+
+		// --- When ---
+		err := json.Unmarshal([]byte(`null`), &have)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		// --- Given ---
+		tim := time.Date(2000, 1, 2, 3, 4, 5, 6, time.UTC)
+		a := Annotated{
+			"time": tim,
+			"list": []any{uint8(1), []any{int64(-1 << 63)}},
+			"ptr":  ptr(uint16(2)),
+			"nil":  (*int)(nil),
+		}
+		data := must.Value(json.Marshal(a))
+
+		// --- When ---
+		var have Annotated
+		err := json.Unmarshal(data, &have)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, a, have)
+	})
+
+	t.Run("error - path not found", func(t *testing.T) {
+		// --- Given ---
+		data := `{"a": [1], "$types": {"/a/1": "uint", "/b": "uint"}}`
+
+		// --- When ---
+		var have Annotated
+		err := json.Unmarshal([]byte(data), &have)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvFormat, err)
+		wMsg := "jsontype: path \"/a/1\": invalid format: path not found"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - unsupported type", func(t *testing.T) {
+		// --- Given ---
+		data := `{"a": 1, "$types": {"/a": "unknown"}}`
+
+		// --- When ---
+		var have Annotated
+		err := json.Unmarshal([]byte(data), &have)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		wMsg := "jsontype: path \"/a\": unsupported type: unknown"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - conversion", func(t *testing.T) {
+		// --- Given ---
+		data := `{"a": {"b": 256}, "$types": {"/a/b": "uint8"}}`
+
+		// --- When ---
+		var have Annotated
+		err := json.Unmarshal([]byte(data), &have)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvRange, err)
+		assert.ErrorContain(t, "jsontype: path \"/a/b\": ", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - invalid annotations", func(t *testing.T) {
+		// --- Given ---
+		data := `{"a": 1, "$types": {"/a": 1}}`
+
+		// --- When ---
+		var have Annotated
+		err := json.Unmarshal([]byte(data), &have)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "jsontype: $types: path \"/a\": invalid type: " +
+			"expected string got json.Number"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - not an object", func(t *testing.T) {
+		// --- When ---
+		var have Annotated
+		err := json.Unmarshal([]byte(`[]`), &have)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "jsontype: invalid type: expected map[string]interface {} " +
+			"got []interface {}"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_UnmarshalAnnotated(t *testing.T) {
+	t.Run("custom registry", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+		data := `{"a": {"r": 1.5}, "$types": {"/a": "circle"}}`
+
+		// --- When ---
+		var have Annotated
+		err := UnmarshalAnnotated(reg, []byte(data), &have)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, Annotated{"a": TCircle{R: 1.5}}, have)
+	})
+
+	t.Run("error - type not allowed", func(t *testing.T) {
+		// --- Given ---
+		data := `{"a": 1, "b": 2, "$types": {"/a": "int", "/b": "uint"}}`
+		opt := WithAllowedTypes(Int)
+
+		// --- When ---
+		var have Annotated
+		err := UnmarshalAnnotated(registry, []byte(data), &have, opt)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrTypeNotAllowed, err)
+		wMsg := `jsontype: path "/b": type not allowed: uint`
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - invalid JSON", func(t *testing.T) {
+		// --- When ---
+		var have Annotated
+		err := UnmarshalAnnotated(registry, []byte(`{!}`), &have)
+
+		// --- Then ---
+		assert.ErrorContain(t, "jsontype: invalid character", err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_MarshalAnnotated(t *testing.T) {
+	t.Run("custom registry", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+		a := Annotated{"a": TCircle{R: 1.5}}
+
+		// --- When ---
+		have, err := MarshalAnnotated(reg, a)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := `{"a": {"r": 1.5}, "$types": {"/a": "circle"}}`
+		assert.JSON(t, want, string(have))
+	})
+}
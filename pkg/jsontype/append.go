@@ -0,0 +1,244 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ctx42/convert/pkg/convert"
+)
+
+// appender appends JSON representation of the value to dst. Returns false
+// when the value cannot be encoded on the fast path, in which case dst must
+// be ignored.
+type appender func(dst []byte, v any) ([]byte, bool)
+
+// appenders are the fast path encoders for types in [DefaultRegistry]. Each
+// must produce the same JSON as [json.Marshal] called with the value returned
+// by the encoder registered for the type name.
+var appenders = map[string]appender{
+	Int:      intAppender[int](),
+	Int8:     intAppender[int8](),
+	Int16:    intAppender[int16](),
+	Int32:    intAppender[int32](),
+	Int64:    intAppender[int64](),
+	Uint:     uintAppender[uint](),
+	Uint8:    uintAppender[uint8](),
+	Uint16:   uintAppender[uint16](),
+	Uint32:   uintAppender[uint32](),
+	Uint64:   uintAppender[uint64](),
+	Float32:  appendFloat32,
+	Float64:  appendFloat64,
+	Byte:     uintAppender[byte](),
+	Rune:     intAppender[rune](),
+	String:   appendStringValue,
+	Bool:     appendBool,
+	Time:     appendTime,
+	Duration: appendDurationValue,
+}
+
+// bufPool is a pool of buffers used by [Marshal].
+var bufPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, 0, 256)
+		return &buf
+	},
+}
+
+// maxPooledBuf is the capacity above which buffers are not returned to the
+// pool, so a single big value does not pin the memory.
+const maxPooledBuf = 64 << 10
+
+// AppendJSON appends JSON representation of the value to dst using encoders
+// from [Registry] and returns the extended buffer. The output is the same as
+// the one returned by [Marshal].
+//
+// Values of built-in types are written directly to the buffer, so when dst
+// has enough capacity, the call doesn't allocate. This holds for registries
+// created with [DefaultRegistry], as long as the encoder for the type is not
+// replaced with [Registry.RegisterEncoder]. Other values are encoded with
+// their encoders and [json.Marshal]. On error, dst is returned unchanged.
+func AppendJSON(dst []byte, reg *Registry, val *Value) ([]byte, error) {
+	if val == nil || val.typ == "" {
+		return dst, convert.ErrInvValue
+	}
+	if u, ok := val.val.(*UnknownValue); ok && u.typ == val.typ {
+		return append(dst, u.raw...), nil
+	}
+
+	n := len(dst)
+	dst = append(dst, `{"type":`...)
+	dst = appendString(dst, val.typ)
+	dst = append(dst, `,"value":`...)
+
+	var ok bool
+	switch app := reg.appender(val.typ); {
+	case val.lazy != nil:
+	case val.val == nil:
+		dst, ok = append(dst, "null"...), true
+	case app != nil:
+		m := len(dst)
+		if dst, ok = app(dst, val.val); !ok {
+			dst = dst[:m]
+		}
+	}
+	if !ok {
+		v, err := val.encode(reg)
+		if err != nil {
+			return dst[:n], fmt.Errorf("jsontype: %w", err)
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return dst[:n], err
+		}
+		dst = append(dst, data...)
+	}
+	return append(dst, '}'), nil
+}
+
+// intAppender returns an appender for signed integers of type T.
+func intAppender[T int | int8 | int16 | int32 | int64]() appender {
+	return func(dst []byte, v any) ([]byte, bool) {
+		i, ok := v.(T)
+		if !ok {
+			return dst, false
+		}
+		return strconv.AppendInt(dst, int64(i), 10), true
+	}
+}
+
+// uintAppender returns an appender for unsigned integers of type T.
+func uintAppender[T uint | uint8 | uint16 | uint32 | uint64]() appender {
+	return func(dst []byte, v any) ([]byte, bool) {
+		i, ok := v.(T)
+		if !ok {
+			return dst, false
+		}
+		return strconv.AppendUint(dst, uint64(i), 10), true
+	}
+}
+
+// appendFloat32 is an appender for float32 values.
+func appendFloat32(dst []byte, v any) ([]byte, bool) {
+	f, ok := v.(float32)
+	if !ok {
+		return dst, false
+	}
+	return appendFloat(dst, float64(f), 32)
+}
+
+// appendFloat64 is an appender for float64 values.
+func appendFloat64(dst []byte, v any) ([]byte, bool) {
+	f, ok := v.(float64)
+	if !ok {
+		return dst, false
+	}
+	return appendFloat(dst, f, 64)
+}
+
+// appendStringValue is an appender for string values.
+func appendStringValue(dst []byte, v any) ([]byte, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return dst, false
+	}
+	return appendString(dst, s), true
+}
+
+// appendBool is an appender for bool values.
+func appendBool(dst []byte, v any) ([]byte, bool) {
+	b, ok := v.(bool)
+	if !ok {
+		return dst, false
+	}
+	return strconv.AppendBool(dst, b), true
+}
+
+// appendTime is an appender for [time.Time] values. It matches the encoder
+// registered by [DefaultRegistry].
+func appendTime(dst []byte, v any) ([]byte, bool) {
+	t, ok := v.(time.Time)
+	if !ok {
+		return dst, false
+	}
+	dst = append(dst, '"')
+	dst = t.AppendFormat(dst, time.RFC3339Nano)
+	return append(dst, '"'), true
+}
+
+// appendDurationValue is an appender for [time.Duration] values. It matches
+// the encoder registered by [DefaultRegistry].
+func appendDurationValue(dst []byte, v any) ([]byte, bool) {
+	d, ok := v.(time.Duration)
+	if !ok {
+		return dst, false
+	}
+	dst = append(dst, '"')
+	dst = appendDuration(dst, d)
+	return append(dst, '"'), true
+}
+
+// hex are the digits used to escape characters in JSON strings.
+const hex = "0123456789abcdef"
+
+// appendString appends the JSON string the same way [json.Marshal] does,
+// including escaping of HTML characters. Strings with control characters
+// (other than whitespace) or invalid UTF-8 are rare, and their encoding
+// depends on the JSON implementation, so they are encoded with
+// [json.Marshal].
+func appendString(dst []byte, s string) []byte {
+	n := len(dst)
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' &&
+				b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch b {
+			case '"', '\\':
+				dst = append(dst, '\\', b)
+			case '<', '>', '&':
+				dst = append(dst, '\\', 'u', '0', '0', hex[b>>4], hex[b&0xF])
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				return appendMarshaled(dst[:n], s)
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			return appendMarshaled(dst[:n], s)
+		case r == '\u2028' || r == '\u2029':
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hex[r&0xF])
+			start = i + size
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
+
+// appendMarshaled appends the string encoded with [json.Marshal].
+func appendMarshaled(dst []byte, s string) []byte {
+	data, _ := json.Marshal(s) // Never fails for strings.
+	return append(dst, data...)
+}
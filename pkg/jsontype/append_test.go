@@ -0,0 +1,278 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ctx42/convert/pkg/convert"
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
+)
+
+// fastValues returns values of all the types with fast path encoders.
+func fastValues() []*Value {
+	tim := time.Date(2000, 1, 2, 3, 4, 5, 600, time.UTC)
+	return []*Value{
+		New(-42), New(int8(-8)), New(int16(-16)), New(int32(-32)),
+		New(int64(math.MinInt64)), New(uint(42)), New(uint8(8)),
+		New(uint16(16)), New(uint32(32)), New(uint64(math.MaxUint64)),
+		New(float32(0.1)), New(1.5), New("abc"), New(true), New(tim),
+		New(90 * time.Minute), {typ: Nil},
+	}
+}
+
+func Test_AppendJSON_tabular(t *testing.T) {
+	cet := time.FixedZone("CET", 3600)
+
+	tt := []struct {
+		testN string
+
+		val *Value
+	}{
+		{"int", New(-42)},
+		{"int8", New(int8(math.MinInt8))},
+		{"int16", New(int16(math.MaxInt16))},
+		{"int32", New(int32(math.MinInt32))},
+		{"int64", New(int64(math.MinInt64))},
+		{"uint", New(uint(42))},
+		{"uint8", New(uint8(math.MaxUint8))},
+		{"uint16", New(uint16(math.MaxUint16))},
+		{"uint32", New(uint32(math.MaxUint32))},
+		{"uint64", New(uint64(math.MaxUint64))},
+		{"byte", &Value{typ: Byte, val: byte(1)}},
+		{"rune", &Value{typ: Rune, val: 'A'}},
+		{"float64 zero", New(0.0)},
+		{"float64 negative zero", New(math.Copysign(0, -1))},
+		{"float64", New(1.5)},
+		{"float64 fraction", New(0.1)},
+		{"float64 small", New(1e-7)},
+		{"float64 small negative", New(-1.5e-10)},
+		{"float64 big", New(1e21)},
+		{"float64 below big", New(1e20)},
+		{"float64 max", New(math.MaxFloat64)},
+		{"float64 smallest", New(math.SmallestNonzeroFloat64)},
+		{"float32", New(float32(0.1))},
+		{"float32 small", New(float32(1e-7))},
+		{"float32 big", New(float32(1e21))},
+		{"float32 max", New(float32(math.MaxFloat32))},
+		{"string", New("abc")},
+		{"string empty", New("")},
+		{"string quotes", New(`"\`)},
+		{"string html", New("<a href='x'>&</a>")},
+		{"string whitespace", New("\n\r\t")},
+		{"string control", New("\x00\x01\b\f\x1f\x7f")},
+		{"string unicode", New("zażółć 日本 🙂")},
+		{"string line separators", New("\u2028\u2029")},
+		{"string invalid utf8", New("a\xffb\xc3")},
+		{"bool true", New(true)},
+		{"bool false", New(false)},
+		{"time", New(time.Date(2000, 1, 2, 3, 4, 5, 600, time.UTC))},
+		{"time zone", New(time.Date(2000, 1, 2, 3, 4, 5, 0, cet))},
+		{"time zero", New(time.Time{})},
+		{"duration zero", New(time.Duration(0))},
+		{"duration nanoseconds", New(999 * time.Nanosecond)},
+		{"duration microseconds", New(1500 * time.Nanosecond)},
+		{"duration milliseconds", New(1500 * time.Microsecond)},
+		{"duration seconds", New(-time.Second)},
+		{"duration fraction", New(time.Minute + 1)},
+		{"duration hours", New(90 * time.Minute)},
+		{"duration max", New(time.Duration(math.MaxInt64))},
+		{"duration min", New(time.Duration(math.MinInt64))},
+		{"nil", &Value{typ: Nil}},
+		{"type mismatch", &Value{typ: Uint, val: "abc"}},
+		{"composite", New([]time.Duration{time.Second})},
+		{"type name escaped", &Value{typ: "<abc>", val: 1}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- Given ---
+			reg := DefaultRegistry()
+			want := must.Value(json.Marshal(tc.val.Map(WithRegistry(reg))))
+
+			// --- When ---
+			have, err := AppendJSON(nil, reg, tc.val)
+
+			// --- Then ---
+			assert.NoError(t, err)
+			assert.Equal(t, string(want), string(have))
+		})
+	}
+}
+
+func Test_AppendJSON(t *testing.T) {
+	t.Run("appends to the buffer", func(t *testing.T) {
+		// --- Given ---
+		dst := []byte("abc")
+
+		// --- When ---
+		have, err := AppendJSON(dst, registry, New(uint(42)))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, `abc{"type":"uint","value":42}`, string(have))
+	})
+
+	t.Run("registry without fast path", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+		reg.RegisterEncoder(Duration, convert.ToAnyAny(DurationToString))
+
+		// --- When ---
+		have, err := AppendJSON(nil, reg, New(time.Minute))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, `{"type":"time.Duration","value":"1m0s"}`, string(have))
+	})
+
+	t.Run("replaced encoder disables the fast path", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+		enc := func(v time.Duration) (int64, error) { return int64(v), nil }
+		reg.RegisterEncoder(Duration, convert.ToAnyAny(enc))
+
+		// --- When ---
+		have, err := AppendJSON(nil, reg, New(time.Second))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := `{"type":"time.Duration","value":1000000000}`
+		assert.Equal(t, want, string(have))
+	})
+
+	t.Run("unknown value", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "abc", "value": 1}`
+		val := must.Value(UnmarshalValue([]byte(data), WithUnknown()))
+
+		// --- When ---
+		have, err := AppendJSON(nil, registry, val)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, data, string(have))
+	})
+
+	t.Run("lazy value", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint", "value": 42}`
+		val := must.Value(UnmarshalValue([]byte(data), WithLazy()))
+
+		// --- When ---
+		have, err := AppendJSON(nil, registry, val)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, `{"type":"uint","value":42}`, string(have))
+	})
+
+	t.Run("error - nil value", func(t *testing.T) {
+		// --- Given ---
+		dst := []byte("abc")
+
+		// --- When ---
+		have, err := AppendJSON(dst, registry, nil)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvValue, err)
+		assert.Equal(t, "abc", string(have))
+	})
+
+	t.Run("error - not representable float", func(t *testing.T) {
+		// --- Given ---
+		dst := []byte("abc")
+
+		// --- When ---
+		have, err := AppendJSON(dst, registry, New(math.NaN()))
+
+		// --- Then ---
+		assert.ErrorContain(t, "unsupported value: NaN", err)
+		assert.Equal(t, "abc", string(have))
+	})
+
+	t.Run("error - encoder", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+		reg.RegisterEncoder(Int, func(any) (any, error) {
+			return nil, errors.New("test")
+		})
+		dst := []byte("abc")
+
+		// --- When ---
+		have, err := AppendJSON(dst, reg, New(1))
+
+		// --- Then ---
+		assert.ErrorEqual(t, "jsontype: test", err)
+		assert.Equal(t, "abc", string(have))
+	})
+}
+
+func Test_AppendJSON_allocations(t *testing.T) {
+	for _, val := range fastValues() {
+		t.Run(val.typ, func(t *testing.T) {
+			// --- Given ---
+			dst := make([]byte, 0, 128)
+
+			// --- When ---
+			have := testing.AllocsPerRun(100, func() {
+				_, _ = AppendJSON(dst, registry, val)
+			})
+
+			// --- Then ---
+			assert.Equal(t, 0.0, have)
+		})
+	}
+}
+
+func Test_Value_MarshalJSON_allocations(t *testing.T) {
+	for _, val := range fastValues() {
+		t.Run(val.typ, func(t *testing.T) {
+			// --- When ---
+			have := testing.AllocsPerRun(100, func() {
+				_, _ = val.MarshalJSON()
+			})
+
+			// --- Then ---
+			assert.Equal(t, 1.0, have)
+		})
+	}
+}
+
+func Benchmark_AppendJSON(b *testing.B) {
+	for _, val := range fastValues() {
+		b.Run(val.typ, func(b *testing.B) {
+			dst := make([]byte, 0, 128)
+			b.ReportAllocs()
+			for b.Loop() {
+				_, _ = AppendJSON(dst, registry, val)
+			}
+		})
+	}
+}
+
+func Benchmark_Value_MarshalJSON(b *testing.B) {
+	for _, val := range fastValues() {
+		b.Run(val.typ, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				_, _ = val.MarshalJSON()
+			}
+		})
+	}
+
+	b.Run("registered struct", func(b *testing.B) {
+		reg := shapeRegistry(b)
+		val := &Value{typ: "circle", val: TCircle{R: 1.5}}
+		b.ReportAllocs()
+		for b.Loop() {
+			_, _ = Marshal(reg, val)
+		}
+	})
+}
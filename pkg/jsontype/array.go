@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+
+	"github.com/ctx42/convert/pkg/convert"
+)
+
+// ReadArray returns an iterator over values of the JSON array read from the
+// stream. The array is the whole document or, when the JSON Pointer (RFC 6901)
+// is not empty, the array it refers to. The document is read token by token,
+// only one element of the array is kept in memory at a time, and values
+// preceding the array are skipped without decoding.
+//
+// Errors for elements which cannot be converted are yielded with element
+// indexes, and reading continues with the next element if the loop is not
+// stopped. Reading stops after the first syntax or reader error. By default,
+// the package-level registry is used, use [WithRegistry] to provide a custom
+// one. The [WithStrict], [WithUnknown] and [WithAllowedTypes] options are
+// supported.
+func ReadArray(
+	r io.Reader,
+	ptr string,
+	opts ...Option,
+) iter.Seq2[*Value, error] {
+
+	def := newOptions(opts...)
+	return func(yield func(*Value, error) bool) {
+		tokens, err := splitPointer(ptr)
+		if err != nil {
+			yield(nil, fmt.Errorf("jsontype: path %q: %w", ptr, err))
+			return
+		}
+		dec := json.NewDecoder(r)
+		if err = seekArray(dec, ptr, tokens); err != nil {
+			yield(nil, fmt.Errorf("jsontype: %w", err))
+			return
+		}
+		for i := 0; dec.More(); i++ {
+			var raw json.RawMessage
+			if err = dec.Decode(&raw); err != nil {
+				yield(nil, fmt.Errorf("jsontype: index %d: %w", i, err))
+				return
+			}
+			val := &Value{}
+			if err = unmarshal(def, raw, val); err != nil {
+				if !yield(nil, fmt.Errorf("index %d: %w", i, err)) {
+					return
+				}
+				continue
+			}
+			if !yield(val, nil) {
+				return
+			}
+		}
+		if _, err = dec.Token(); err != nil {
+			yield(nil, fmt.Errorf("jsontype: %w", err))
+		}
+	}
+}
+
+// seekArray reads tokens until the opening bracket of the array at the path
+// described by reference tokens.
+func seekArray(dec *json.Decoder, ptr string, tokens []string) error {
+	for _, token := range tokens {
+		found, err := seekToken(dec, token)
+		if err != nil {
+			return err
+		}
+		if !found {
+			format := "path %q: %w: path not found"
+			return fmt.Errorf(format, ptr, convert.ErrInvFormat)
+		}
+	}
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != json.Delim('[') {
+		format := "path %q: %w: expected array"
+		return fmt.Errorf(format, ptr, convert.ErrInvType)
+	}
+	return nil
+}
+
+// seekToken reads tokens until the beginning of the object field or array
+// element referenced by the token. Returns false when it does not exist.
+func seekToken(dec *json.Decoder, token string) (bool, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return false, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return false, err
+			}
+			if key == token {
+				return true, nil
+			}
+			if err = skipValue(dec); err != nil {
+				return false, err
+			}
+		}
+		return false, nil
+
+	case json.Delim('['):
+		idx, err := strconv.Atoi(token)
+		if err != nil || idx < 0 {
+			return false, nil
+		}
+		for ; idx > 0 && dec.More(); idx-- {
+			if err = skipValue(dec); err != nil {
+				return false, err
+			}
+		}
+		return dec.More(), nil
+
+	default:
+		return false, nil
+	}
+}
+
+// skipValue reads tokens of the next value without decoding it.
+func skipValue(dec *json.Decoder) error {
+	var depth int
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
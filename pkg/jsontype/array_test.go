@@ -0,0 +1,273 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctx42/convert/pkg/convert"
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+// arrayReader generates JSON array with n typed values.
+type arrayReader struct {
+	n, i int
+	buf  []byte
+}
+
+func (r *arrayReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		switch {
+		case r.i == 0:
+			r.buf = []byte(`[`)
+		case r.i <= r.n:
+			r.buf = fmt.Appendf(nil, `{"type": "uint64", "value": %d}`, r.i)
+			if r.i < r.n {
+				r.buf = append(r.buf, ',')
+			}
+		case r.i == r.n+1:
+			r.buf = []byte(`]`)
+		default:
+			return 0, io.EOF
+		}
+		r.i++
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// collectArray returns values and errors from the iterator.
+func collectArray(
+	t *testing.T,
+	data, ptr string,
+	opts ...Option,
+) ([]*Value, []error) {
+
+	t.Helper()
+	var vals []*Value
+	var errs []error
+	for val, err := range ReadArray(strings.NewReader(data), ptr, opts...) {
+		vals = append(vals, val)
+		errs = append(errs, err)
+	}
+	return vals, errs
+}
+
+func Test_ReadArray(t *testing.T) {
+	t.Run("top-level array", func(t *testing.T) {
+		// --- Given ---
+		data := `[
+			{"type": "uint64", "value": 18446744073709551615},
+			{"type": "time.Duration", "value": "1s"}
+		]`
+
+		// --- When ---
+		vals, errs := collectArray(t, data, "")
+
+		// --- Then ---
+		assert.Equal(t, []error{nil, nil}, errs)
+		want := []*Value{
+			{typ: "uint64", val: uint64(1<<64 - 1)},
+			{typ: "time.Duration", val: time.Second},
+		}
+		assert.Equal(t, want, vals)
+	})
+
+	t.Run("empty array", func(t *testing.T) {
+		// --- When ---
+		vals, errs := collectArray(t, `[]`, "")
+
+		// --- Then ---
+		assert.Nil(t, vals)
+		assert.Nil(t, errs)
+	})
+
+	t.Run("array at pointer", func(t *testing.T) {
+		// --- Given ---
+		data := `{
+			"skip": {"a": [1, {"b": [2]}], "c": "d"},
+			"data": [
+				{"items": "skip"},
+				{"a/b": [{"type": "uint8", "value": 1}]}
+			]
+		}`
+
+		// --- When ---
+		vals, errs := collectArray(t, data, "/data/1/a~1b")
+
+		// --- Then ---
+		assert.Equal(t, []error{nil}, errs)
+		assert.Equal(t, []*Value{{typ: "uint8", val: uint8(1)}}, vals)
+	})
+
+	t.Run("custom registry", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+		data := `[{"type": "circle", "value": {"r": 1}}]`
+
+		// --- When ---
+		vals, errs := collectArray(t, data, "", WithRegistry(reg))
+
+		// --- Then ---
+		assert.Equal(t, []error{nil}, errs)
+		assert.Equal(t, []*Value{{typ: "circle", val: TCircle{R: 1}}}, vals)
+	})
+
+	t.Run("large generated array", func(t *testing.T) {
+		// --- Given ---
+		r := &arrayReader{n: 10000}
+
+		// --- When ---
+		var cnt int
+		var last *Value
+		for val, err := range ReadArray(r, "") {
+			assert.NoError(t, err)
+			last = val
+			cnt++
+		}
+
+		// --- Then ---
+		assert.Equal(t, 10000, cnt)
+		assert.Equal(t, uint64(10000), last.val)
+	})
+
+	t.Run("conversion errors have indexes", func(t *testing.T) {
+		// --- Given ---
+		data := `[
+			{"type": "uint8", "value": 256},
+			{"type": "unknown", "value": 1},
+			{"type": "uint8", "value": 2}
+		]`
+
+		// --- When ---
+		vals, errs := collectArray(t, data, "")
+
+		// --- Then ---
+		assert.Len(t, 3, errs)
+		assert.ErrorIs(t, convert.ErrInvRange, errs[0])
+		assert.ErrorContain(t, "index 0: jsontype: ", errs[0])
+		assert.ErrorEqual(t, "index 1: unsupported type: unknown", errs[1])
+		assert.NoError(t, errs[2])
+		assert.Equal(t, []*Value{nil, nil, {typ: "uint8", val: uint8(2)}}, vals)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		// --- Given ---
+		data := `[{"type": "uint8", "value": 1}, {"type": "uint8", "value": 2}]`
+
+		// --- When ---
+		var vals []*Value
+		for val := range ReadArray(strings.NewReader(data), "") {
+			vals = append(vals, val)
+			break
+		}
+
+		// --- Then ---
+		assert.Equal(t, []*Value{{typ: "uint8", val: uint8(1)}}, vals)
+	})
+
+	t.Run("stop on error", func(t *testing.T) {
+		// --- Given ---
+		data := `[
+			{"type": "uint8", "value": 256},
+			{"type": "uint8", "value": 2}
+		]`
+
+		// --- When ---
+		var errs []error
+		for _, err := range ReadArray(strings.NewReader(data), "") {
+			errs = append(errs, err)
+			break
+		}
+
+		// --- Then ---
+		assert.Len(t, 1, errs)
+		assert.ErrorContain(t, "index 0: ", errs[0])
+	})
+
+	t.Run("error - invalid pointer", func(t *testing.T) {
+		// --- When ---
+		vals, errs := collectArray(t, `[]`, "a")
+
+		// --- Then ---
+		assert.Equal(t, []*Value{nil}, vals)
+		assert.ErrorIs(t, convert.ErrInvFormat, errs[0])
+		wMsg := "jsontype: path \"a\": invalid format: " +
+			"JSON Pointer must start with a slash"
+		assert.ErrorEqual(t, wMsg, errs[0])
+	})
+
+	t.Run("error - path not found", func(t *testing.T) {
+		// --- Given ---
+		tt := []struct {
+			data string
+			ptr  string
+		}{
+			{`{"a": []}`, "/b"},
+			{`{"a": [[]]}`, "/a/1"},
+			{`{"a": [[]]}`, "/a/x"},
+			{`{"a": 1}`, "/a/0"},
+		}
+
+		for _, tc := range tt {
+			// --- When ---
+			vals, errs := collectArray(t, tc.data, tc.ptr)
+
+			// --- Then ---
+			assert.Equal(t, []*Value{nil}, vals)
+			assert.ErrorIs(t, convert.ErrInvFormat, errs[0])
+			wMsg := "jsontype: path %q: invalid format: path not found"
+			assert.ErrorEqual(t, fmt.Sprintf(wMsg, tc.ptr), errs[0])
+		}
+	})
+
+	t.Run("error - not an array", func(t *testing.T) {
+		// --- When ---
+		vals, errs := collectArray(t, `{"a": {}}`, "/a")
+
+		// --- Then ---
+		assert.Equal(t, []*Value{nil}, vals)
+		assert.ErrorIs(t, convert.ErrInvType, errs[0])
+		wMsg := "jsontype: path \"/a\": invalid type: expected array"
+		assert.ErrorEqual(t, wMsg, errs[0])
+	})
+
+	t.Run("error - syntax in element", func(t *testing.T) {
+		// --- Given ---
+		data := `[{"type": "uint8", "value": 1}, {!}, {"type": "uint8"}]`
+
+		// --- When ---
+		vals, errs := collectArray(t, data, "")
+
+		// --- Then ---
+		assert.Len(t, 2, errs)
+		assert.NoError(t, errs[0])
+		assert.ErrorContain(t, "jsontype: index 1: invalid character", errs[1])
+		assert.Equal(t, []*Value{{typ: "uint8", val: uint8(1)}, nil}, vals)
+	})
+
+	t.Run("error - syntax while seeking", func(t *testing.T) {
+		// --- When ---
+		vals, errs := collectArray(t, `{"a": [}`, "/b")
+
+		// --- Then ---
+		assert.Equal(t, []*Value{nil}, vals)
+		assert.ErrorContain(t, "jsontype: invalid character", errs[0])
+	})
+	t.Run("error - unterminated array", func(t *testing.T) {
+		// --- When ---
+		vals, errs := collectArray(t, `[{"type": "uint8", "value": 1}`, "")
+
+		// --- Then ---
+		assert.Len(t, 2, errs)
+		assert.NoError(t, errs[0])
+		assert.ErrorContain(t, "jsontype: ", errs[1])
+		assert.Equal(t, []*Value{{typ: "uint8", val: uint8(1)}, nil}, vals)
+	})
+}
@@ -0,0 +1,191 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ctx42/convert/pkg/convert"
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
+)
+
+// codec represents JSON implementation used in the shared tests.
+type codec struct {
+	name      string
+	marshal   func(v any) ([]byte, error)
+	unmarshal func(data []byte, v any) error
+}
+
+// codecs are JSON implementations the shared tests run with. Codecs
+// available only with build tags are added in init functions.
+var codecs = []codec{{"v1", json.Marshal, json.Unmarshal}}
+
+func Test_Value_codecs_tabular(t *testing.T) {
+	tim := time.Date(2000, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	tt := []struct {
+		testN string
+
+		val  *Value
+		want string
+	}{
+		{"nil", &Value{typ: Nil}, `{"type": "nil", "value": null}`},
+		{"bool", New(true), `{"type": "bool", "value": true}`},
+		{"string", New("<a&b>"), `{"type": "string", "value": "<a&b>"}`},
+		{"int8", New(int8(-128)), `{"type": "int8", "value": -128}`},
+		{
+			"int64",
+			New(int64(-1 << 63)),
+			`{"type": "int64", "value": -9223372036854775808}`,
+		},
+		{
+			"uint64",
+			New(uint64(1<<64 - 1)),
+			`{"type": "uint64", "value": 18446744073709551615}`,
+		},
+		{"float64", New(1.5), `{"type": "float64", "value": 1.5}`},
+		{
+			"time",
+			New(tim),
+			`{"type": "time.Time", "value": "2000-01-02T03:04:05.000000006Z"}`,
+		},
+		{
+			"duration",
+			New(time.Second),
+			`{"type": "time.Duration", "value": "1s"}`,
+		},
+		{"slice", New([]uint16{1, 2}), `{"type": "[]uint16", "value": [1, 2]}`},
+		{
+			"map",
+			New(map[string]int{"a": 1}),
+			`{"type": "map[string]int", "value": {"a": 1}}`,
+		},
+		{"pointer", New(ptr(uint(1))), `{"type": "*uint", "value": 1}`},
+		{"nil pointer", New[*uint](nil), `{"type": "*uint", "value": null}`},
+	}
+
+	for _, cd := range codecs {
+		for _, tc := range tt {
+			t.Run(cd.name+" "+tc.testN, func(t *testing.T) {
+				// --- When ---
+				data, err := cd.marshal(tc.val)
+
+				// --- Then ---
+				assert.NoError(t, err)
+				assert.JSON(t, tc.want, string(data))
+
+				have := &Value{}
+				assert.NoError(t, cd.unmarshal(data, have))
+				assert.Equal(t, tc.val, have)
+			})
+		}
+	}
+}
+
+func Test_Value_codecs(t *testing.T) {
+	for _, cd := range codecs {
+		t.Run(cd.name+" nested", func(t *testing.T) {
+			// --- Given ---
+			type T struct {
+				A *Value `json:"a"`
+				B int    `json:"b"`
+			}
+			src := T{A: New(uint8(1)), B: 2}
+
+			// --- When ---
+			data, err := cd.marshal(src)
+
+			// --- Then ---
+			assert.NoError(t, err)
+			want := `{"a": {"type": "uint8", "value": 1}, "b": 2}`
+			assert.JSON(t, want, string(data))
+			var have T
+			assert.NoError(t, cd.unmarshal(data, &have))
+			assert.Equal(t, src, have)
+		})
+
+		t.Run(cd.name+" unknown value", func(t *testing.T) {
+			// --- Given ---
+			data := `{"type":"money","value":{"b":1,"a":2}}`
+			val := must.Value(UnmarshalValue([]byte(data), WithUnknown()))
+
+			// --- When ---
+			have, err := cd.marshal(val)
+
+			// --- Then ---
+			assert.NoError(t, err)
+			assert.Equal(t, data, string(have))
+		})
+
+		t.Run(cd.name+" case insensitive field names", func(t *testing.T) {
+			// --- Given ---
+			data := `{"Type": "uint", "VALUE": 1, "other": [1, {}]}`
+
+			// --- When ---
+			have := &Value{}
+			err := cd.unmarshal([]byte(data), have)
+
+			// --- Then ---
+			assert.NoError(t, err)
+			assert.Equal(t, New(uint(1)), have)
+		})
+
+		t.Run(cd.name+" error - marshal invalid value", func(t *testing.T) {
+			// --- When ---
+			_, err := cd.marshal(&Value{})
+
+			// --- Then ---
+			assert.ErrorIs(t, convert.ErrInvValue, err)
+		})
+
+		t.Run(cd.name+" error - unsupported type", func(t *testing.T) {
+			// --- Given ---
+			data := `{"type": "unknown", "value": 1}`
+
+			// --- When ---
+			err := cd.unmarshal([]byte(data), &Value{})
+
+			// --- Then ---
+			assert.ErrorIs(t, convert.ErrUnsType, err)
+		})
+
+		t.Run(cd.name+" error - null", func(t *testing.T) {
+			// --- When ---
+			err := cd.unmarshal([]byte(`null`), &Value{})
+
+			// --- Then ---
+			assert.ErrorIs(t, convert.ErrUnsType, err)
+		})
+
+		t.Run(cd.name+" error - conversion", func(t *testing.T) {
+			// --- Given ---
+			data := `{"type": "uint8", "value": 256}`
+
+			// --- When ---
+			err := cd.unmarshal([]byte(data), &Value{})
+
+			// --- Then ---
+			assert.ErrorIs(t, convert.ErrInvRange, err)
+		})
+
+		t.Run(cd.name+" error - not an object", func(t *testing.T) {
+			// --- When ---
+			err := cd.unmarshal([]byte(`[]`), &Value{})
+
+			// --- Then ---
+			assert.Error(t, err)
+		})
+
+		t.Run(cd.name+" error - type not a string", func(t *testing.T) {
+			// --- When ---
+			err := cd.unmarshal([]byte(`{"type": 1}`), &Value{})
+
+			// --- Then ---
+			assert.Error(t, err)
+		})
+	}
+}
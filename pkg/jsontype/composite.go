@@ -0,0 +1,475 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ctx42/convert/pkg/convert"
+)
+
+// Reflected types used by encoders.
+var (
+	typAny        = reflect.TypeFor[any]()
+	typString     = reflect.TypeFor[string]()
+	typAnySlice   = reflect.TypeFor[[]any]()
+	typAnyMap     = reflect.TypeFor[map[string]any]()
+	typAnyPointer = reflect.TypeFor[*any]()
+)
+
+// Limits for composite types built from type names. Type names may come from
+// untrusted input, so the length and nesting depth of type names (each level
+// builds a Go type, which is never freed), and the size of arrays (and their
+// zero values) are limited.
+const (
+	maxTypeLen   = 256      // Maximum length of composite type names.
+	maxTypeDepth = 16       // Maximum nesting depth of composite types.
+	maxArrayLen  = 4096     // Maximum array length.
+	maxArraySize = 64 << 10 // Maximum array size in bytes.
+)
+
+// typeExpr represents a parsed composite type name.
+type typeExpr struct {
+	kind reflect.Kind // One of reflect.Slice, Array, Map or Pointer.
+	len  int          // Array length.
+	key  string       // Map key type name.
+	elem string       // Element type name.
+}
+
+// parseType parses the outermost level of a composite type name in the format
+// returned by [reflect.Type.String], for example, "[]uint64", "[2]int",
+// "map[string]time.Duration" or "*int". Returns false if the type name is not
+// a slice, array, map or pointer type name, or the array length exceeds
+// [maxArrayLen].
+func parseType(typ string) (typeExpr, bool) {
+	switch {
+	case strings.HasPrefix(typ, "*"):
+		if elem := typ[1:]; elem != "" {
+			return typeExpr{kind: reflect.Pointer, elem: elem}, true
+		}
+
+	case strings.HasPrefix(typ, "[]"):
+		if elem := typ[2:]; elem != "" {
+			return typeExpr{kind: reflect.Slice, elem: elem}, true
+		}
+
+	case strings.HasPrefix(typ, "["):
+		end := strings.IndexByte(typ, ']')
+		if end < 0 || end == len(typ)-1 {
+			return typeExpr{}, false
+		}
+		num := typ[1:end]
+		n, err := strconv.Atoi(num)
+		if err != nil || n < 0 || n > maxArrayLen || num[0] == '+' {
+			return typeExpr{}, false
+		}
+		return typeExpr{kind: reflect.Array, len: n, elem: typ[end+1:]}, true
+
+	case strings.HasPrefix(typ, "map["):
+		depth := 0
+		for i := 3; i < len(typ); i++ {
+			switch typ[i] {
+			case '[':
+				depth++
+			case ']':
+				depth--
+			}
+			if depth > 0 {
+				continue
+			}
+			key, elem := typ[4:i], typ[i+1:]
+			if key == "" || elem == "" {
+				return typeExpr{}, false
+			}
+			return typeExpr{kind: reflect.Map, key: key, elem: elem}, true
+		}
+	}
+	return typeExpr{}, false
+}
+
+// typeDepth returns the nesting depth of the composite type name, for
+// example, 2 for "[]*int". Returns 0 for type names which are not composite.
+func typeDepth(typ string) int {
+	expr, ok := parseType(typ)
+	if !ok {
+		return 0
+	}
+	depth := typeDepth(expr.elem)
+	if expr.kind == reflect.Map {
+		depth = max(depth, typeDepth(expr.key))
+	}
+	return depth + 1
+}
+
+// isSupported returns true if the composite type name doesn't exceed
+// [maxTypeLen] and [maxTypeDepth] limits. It must be checked before the Go
+// type is built.
+func isSupported(typ string) bool {
+	return len(typ) <= maxTypeLen && typeDepth(typ) <= maxTypeDepth
+}
+
+// compositeConverter returns a converter and the Go type for the composite
+// type name. Converters for the elements are looked up in the registry.
+// Returns nil converter when the type name is not a composite type name, when
+// it exceeds [maxTypeLen] or [maxTypeDepth], when any of its elements is not
+// registered with the Go type, or when the array size would exceed
+// [maxArraySize].
+func compositeConverter(
+	reg *Registry,
+	typ string,
+) (convert.AnyToAny, reflect.Type) {
+
+	expr, ok := parseType(typ)
+	if !ok || !isSupported(typ) {
+		return nil, nil
+	}
+	elemCnv, elemTyp := reg.lookup(expr.elem)
+	if elemCnv == nil || elemTyp == nil {
+		return nil, nil
+	}
+	elemCnv = reg.decoding(expr.elem, elemCnv)
+
+	switch expr.kind {
+	case reflect.Slice:
+		rt := reflect.SliceOf(elemTyp)
+		return sliceConverter(rt, elemCnv), rt
+
+	case reflect.Array:
+		size := elemTyp.Size()
+		if expr.len > 0 && size > maxArraySize/uintptr(expr.len) {
+			return nil, nil
+		}
+		rt := reflect.ArrayOf(expr.len, elemTyp)
+		return arrayConverter(rt, elemCnv), rt
+
+	case reflect.Pointer:
+		rt := reflect.PointerTo(elemTyp)
+		return pointerConverter(rt, elemCnv), rt
+
+	default:
+		keyCnv, keyTyp := reg.lookup(expr.key)
+		if keyCnv == nil || keyTyp == nil || !isMapKey(keyTyp) {
+			return nil, nil
+		}
+		keyCnv = reg.decoding(expr.key, keyCnv)
+		rt := reflect.MapOf(keyTyp, elemTyp)
+		txt := reg.Encoder(expr.key) != nil
+		return mapConverter(rt, keyCnv, elemCnv, txt), rt
+	}
+}
+
+// compositeEncoder returns an encoder for the composite type name built from
+// the element encoders registered in the registry. Returns nil when the type
+// name is not a composite type name, it exceeds the limits (see
+// [isSupported]) or when its element has no encoder. Map keys are encoded
+// with the encoder registered for the key type, keys without it are encoded
+// by [json.Marshal].
+func compositeEncoder(reg *Registry, typ string) convert.AnyToAny {
+	expr, ok := parseType(typ)
+	if !ok || !isSupported(typ) {
+		return nil
+	}
+	enc := reg.Encoder(expr.elem)
+	if expr.kind == reflect.Map {
+		keyEnc := reg.Encoder(expr.key)
+		if keyEnc == nil && enc == nil {
+			return nil
+		}
+		return mapEncoder(keyEnc, enc)
+	}
+	if enc == nil {
+		return nil
+	}
+	switch expr.kind {
+	case reflect.Pointer:
+		return pointerEncoder(enc)
+	default:
+		return listEncoder(enc)
+	}
+}
+
+// listEncoder returns an encoder for slices and arrays which encodes each
+// element with the given encoder. Nil slices are encoded as nil.
+func listEncoder(enc convert.AnyToAny) convert.AnyToAny {
+	return func(value any) (any, error) {
+		rv := reflect.ValueOf(value)
+		switch rv.Kind() {
+		case reflect.Slice:
+			if rv.IsNil() {
+				return nil, nil
+			}
+		case reflect.Array:
+		default:
+			return nil, invTypeError(typAnySlice, value)
+		}
+		dst := make([]any, rv.Len())
+		for i := range dst {
+			var err error
+			if dst[i], err = encodeValue(enc, rv.Index(i)); err != nil {
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+		return dst, nil
+	}
+}
+
+// mapEncoder returns an encoder for maps which encodes each map key with the
+// key encoder and each map value with the given encoder. The key encoder must
+// return strings. Keys or values are not encoded when their encoder is nil.
+// Nil maps are encoded as nil.
+func mapEncoder(keyEnc, enc convert.AnyToAny) convert.AnyToAny {
+	return func(value any) (any, error) {
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Map {
+			return nil, invTypeError(typAnyMap, value)
+		}
+		if rv.IsNil() {
+			return nil, nil
+		}
+		kt := rv.Type().Key()
+		if keyEnc != nil {
+			kt = typString
+		}
+		dst := reflect.MakeMapWithSize(reflect.MapOf(kt, typAny), rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			kv, err := encodeKey(keyEnc, iter.Key())
+			if err != nil {
+				return nil, fmt.Errorf("key %v: %w", iter.Key(), err)
+			}
+			elem := iter.Value().Interface()
+			if enc != nil {
+				if elem, err = encodeValue(enc, iter.Value()); err != nil {
+					return nil, fmt.Errorf("key %v: %w", iter.Key(), err)
+				}
+			}
+			ev := reflect.New(typAny).Elem()
+			if elem != nil {
+				ev.Set(reflect.ValueOf(elem))
+			}
+			dst.SetMapIndex(kv, ev)
+		}
+		return dst.Interface(), nil
+	}
+}
+
+// encodeKey encodes the map key with the encoder. Returns the key as it is
+// when the encoder is nil. Returns an error when the encoder doesn't return
+// a string.
+func encodeKey(enc convert.AnyToAny, rv reflect.Value) (reflect.Value, error) {
+	if enc == nil {
+		return rv, nil
+	}
+	v, err := enc(rv.Interface())
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	str, ok := v.(string)
+	if !ok {
+		return reflect.Value{}, invTypeError(typString, v)
+	}
+	return reflect.ValueOf(str), nil
+}
+
+// pointerEncoder returns an encoder for pointers which encodes the value the
+// pointer points to with the given encoder. Nil pointers are encoded as nil.
+func pointerEncoder(enc convert.AnyToAny) convert.AnyToAny {
+	return func(value any) (any, error) {
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Pointer {
+			return nil, invTypeError(typAnyPointer, value)
+		}
+		if rv.IsNil() {
+			return nil, nil
+		}
+		v, err := encodeValue(enc, rv.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
+// encodeValue encodes the value with the given encoder. Nil values are not
+// passed to the encoder.
+func encodeValue(enc convert.AnyToAny, rv reflect.Value) (any, error) {
+	v := rv.Interface()
+	if v == nil {
+		return nil, nil
+	}
+	return enc(v)
+}
+
+// sliceConverter returns a converter for the slice type. It expects a JSON
+// array or null. Slices of bytes are expected to be base64 encoded strings,
+// the same way [json.Marshal] encodes them.
+func sliceConverter(rt reflect.Type, cnv convert.AnyToAny) convert.AnyToAny {
+	return func(value any) (any, error) {
+		if value == nil {
+			return reflect.Zero(rt).Interface(), nil
+		}
+		if reflect.TypeOf(value) == rt {
+			return value, nil
+		}
+		if str, ok := value.(string); ok && rt.Elem().Kind() == reflect.Uint8 {
+			bs, err := base64.StdEncoding.DecodeString(str)
+			if err != nil {
+				return nil, convert.NewError(convert.ErrInvFormat, "string", rt)
+			}
+			return reflect.ValueOf(bs).Convert(rt).Interface(), nil
+		}
+		src, ok := value.([]any)
+		if !ok {
+			return nil, invTypeError(rt, value)
+		}
+		dst := reflect.MakeSlice(rt, len(src), len(src))
+		if err := setElems(dst, cnv, src); err != nil {
+			return nil, err
+		}
+		return dst.Interface(), nil
+	}
+}
+
+// arrayConverter returns a converter for the array type. It expects a JSON
+// array with exactly the array length elements or null.
+func arrayConverter(rt reflect.Type, cnv convert.AnyToAny) convert.AnyToAny {
+	return func(value any) (any, error) {
+		if value == nil {
+			return reflect.Zero(rt).Interface(), nil
+		}
+		if reflect.TypeOf(value) == rt {
+			return value, nil
+		}
+		src, ok := value.([]any)
+		if !ok {
+			return nil, invTypeError(rt, value)
+		}
+		if len(src) != rt.Len() {
+			format := "%w: expected %d elements got %d"
+			err := fmt.Errorf(format, convert.ErrInvValue, rt.Len(), len(src))
+			return nil, err
+		}
+		dst := reflect.New(rt).Elem()
+		if err := setElems(dst, cnv, src); err != nil {
+			return nil, err
+		}
+		return dst.Interface(), nil
+	}
+}
+
+// mapConverter returns a converter for the map type. It expects a JSON object
+// or null. Integer keys are passed to the key converter as [json.Number],
+// unless txt is true, then all keys are passed as strings. It's the case for
+// key types with encoders, which encode keys as strings.
+func mapConverter(
+	rt reflect.Type,
+	keyCnv, cnv convert.AnyToAny,
+	txt bool,
+) convert.AnyToAny {
+
+	return func(value any) (any, error) {
+		if value == nil {
+			return reflect.Zero(rt).Interface(), nil
+		}
+		if reflect.TypeOf(value) == rt {
+			return value, nil
+		}
+		src, ok := value.(map[string]any)
+		if !ok {
+			return nil, invTypeError(rt, value)
+		}
+		dst := reflect.MakeMapWithSize(rt, len(src))
+		for key, elem := range src {
+			var k any = key
+			if !txt && rt.Key().Kind() != reflect.String {
+				k = json.Number(key)
+			}
+			kv := reflect.New(rt.Key()).Elem()
+			if err := setElem(kv, keyCnv, k); err != nil {
+				return nil, fmt.Errorf("key %q: %w", key, err)
+			}
+			ev := reflect.New(rt.Elem()).Elem()
+			if err := setElem(ev, cnv, elem); err != nil {
+				return nil, fmt.Errorf("key %q: %w", key, err)
+			}
+			dst.SetMapIndex(kv, ev)
+		}
+		return dst.Interface(), nil
+	}
+}
+
+// pointerConverter returns a converter for the pointer type. JSON null is
+// converted to the nil pointer of the pointer type, so the type is preserved.
+// Other values are converted to the element type and a pointer to them is
+// returned.
+func pointerConverter(rt reflect.Type, cnv convert.AnyToAny) convert.AnyToAny {
+	return func(value any) (any, error) {
+		if value == nil {
+			return reflect.Zero(rt).Interface(), nil
+		}
+		if reflect.TypeOf(value) == rt {
+			return value, nil
+		}
+		dst := reflect.New(rt.Elem())
+		if err := setElem(dst.Elem(), cnv, value); err != nil {
+			return nil, err
+		}
+		return dst.Interface(), nil
+	}
+}
+
+// setElems converts the values and sets them as elements of the slice or
+// array. The dst must have the same length as src.
+func setElems(dst reflect.Value, cnv convert.AnyToAny, src []any) error {
+	for i, elem := range src {
+		if err := setElem(dst.Index(i), cnv, elem); err != nil {
+			return fmt.Errorf("index %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// setElem converts the value and sets it to dst. Returns an error when the
+// conversion fails, or the converter returns a value not assignable to dst.
+func setElem(dst reflect.Value, cnv convert.AnyToAny, value any) error {
+	ret, err := cnv(value)
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(ret)
+	if !rv.IsValid() {
+		dst.SetZero()
+		return nil
+	}
+	if !rv.Type().AssignableTo(dst.Type()) {
+		return invTypeError(dst.Type(), ret)
+	}
+	dst.Set(rv)
+	return nil
+}
+
+// isMapKey returns true if the type can be used as a key of a map decoded from
+// a JSON object.
+func isMapKey(rt reflect.Type) bool {
+	switch rt.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// invTypeError returns [convert.ErrInvType] error for the unexpected value.
+func invTypeError(want reflect.Type, have any) error {
+	format := "%w: expected %s got %T"
+	return fmt.Errorf(format, convert.ErrInvType, want, have)
+}
@@ -0,0 +1,1084 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctx42/convert/pkg/convert"
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
+)
+
+func Test_parseType_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		typ  string
+		want typeExpr
+		ok   bool
+	}{
+		{"slice", "[]int", typeExpr{kind: reflect.Slice, elem: "int"}, true},
+		{
+			"nested slice",
+			"[][]time.Time",
+			typeExpr{kind: reflect.Slice, elem: "[]time.Time"},
+			true,
+		},
+		{
+			"array",
+			"[3]uint8",
+			typeExpr{kind: reflect.Array, len: 3, elem: "uint8"},
+			true,
+		},
+		{
+			"empty array",
+			"[0]int",
+			typeExpr{kind: reflect.Array, len: 0, elem: "int"},
+			true,
+		},
+		{
+			"map",
+			"map[string]time.Duration",
+			typeExpr{kind: reflect.Map, key: "string", elem: "time.Duration"},
+			true,
+		},
+		{
+			"map of maps",
+			"map[int]map[string][]int",
+			typeExpr{kind: reflect.Map, key: "int", elem: "map[string][]int"},
+			true,
+		},
+		{
+			"map with array key",
+			"map[[2]int]string",
+			typeExpr{kind: reflect.Map, key: "[2]int", elem: "string"},
+			true,
+		},
+		{"pointer", "*int", typeExpr{kind: reflect.Pointer, elem: "int"}, true},
+		{
+			"pointer to pointer",
+			"**time.Time",
+			typeExpr{kind: reflect.Pointer, elem: "*time.Time"},
+			true,
+		},
+		{
+			"slice of pointers",
+			"[]*int",
+			typeExpr{kind: reflect.Slice, elem: "*int"},
+			true,
+		},
+		{"pointer without element", "*", typeExpr{}, false},
+		{"leaf", "uint64", typeExpr{}, false},
+		{"empty", "", typeExpr{}, false},
+		{"slice without element", "[]", typeExpr{}, false},
+		{"array without element", "[2]", typeExpr{}, false},
+		{"array negative length", "[-1]int", typeExpr{}, false},
+		{"array plus length", "[+1]int", typeExpr{}, false},
+		{"array invalid length", "[a]int", typeExpr{}, false},
+		{
+			"array max length",
+			"[4096]int",
+			typeExpr{kind: reflect.Array, len: maxArrayLen, elem: "int"},
+			true,
+		},
+		{"array too long", "[4097]int", typeExpr{}, false},
+		{
+			"array length overflow",
+			"[9223372036854775808]int",
+			typeExpr{},
+			false,
+		},
+		{"array not closed", "[2int", typeExpr{}, false},
+		{"map without key", "map[]int", typeExpr{}, false},
+		{"map without element", "map[string]", typeExpr{}, false},
+		{"map not closed", "map[string", typeExpr{}, false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- When ---
+			have, ok := parseType(tc.typ)
+
+			// --- Then ---
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
+
+func Test_typeDepth_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		typ  string
+		want int
+	}{
+		{"not composite", "int", 0},
+		{"slice", "[]int", 1},
+		{"pointer to slice", "*[]int", 2},
+		{"array of maps", "[2]map[string]int", 2},
+		{"map key deeper", "map[[1][1]int]int", 3},
+		{"map element deeper", "map[int][][]int", 3},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- When ---
+			have := typeDepth(tc.typ)
+
+			// --- Then ---
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
+
+func Test_isSupported(t *testing.T) {
+	assert.True(t, isSupported("[]int"))
+	assert.True(t, isSupported(strings.Repeat("[]", 16)+"int"))
+	assert.False(t, isSupported(strings.Repeat("[]", 17)+"int"))
+	assert.True(t, isSupported("map[string]"+strings.Repeat("a", 245)))
+	assert.False(t, isSupported("map[string]"+strings.Repeat("a", 246)))
+}
+
+func Test_compositeConverter(t *testing.T) {
+	t.Run("slice", func(t *testing.T) {
+		// --- When ---
+		cnv, rt := compositeConverter(DefaultRegistry(), "[]uint64")
+
+		// --- Then ---
+		assert.NotNil(t, cnv)
+		assert.Equal(t, reflect.TypeFor[[]uint64](), rt)
+	})
+
+	t.Run("array", func(t *testing.T) {
+		// --- When ---
+		cnv, rt := compositeConverter(DefaultRegistry(), "[2]int")
+
+		// --- Then ---
+		assert.NotNil(t, cnv)
+		assert.Equal(t, reflect.TypeFor[[2]int](), rt)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		// --- When ---
+		cnv, rt := compositeConverter(DefaultRegistry(), "map[uint8][]string")
+
+		// --- Then ---
+		assert.NotNil(t, cnv)
+		assert.Equal(t, reflect.TypeFor[map[uint8][]string](), rt)
+	})
+
+	t.Run("pointer", func(t *testing.T) {
+		// --- When ---
+		cnv, rt := compositeConverter(DefaultRegistry(), "*[]int")
+
+		// --- Then ---
+		assert.NotNil(t, cnv)
+		assert.Equal(t, reflect.TypeFor[*[]int](), rt)
+	})
+
+	t.Run("not composite", func(t *testing.T) {
+		// --- When ---
+		cnv, rt := compositeConverter(DefaultRegistry(), "int")
+
+		// --- Then ---
+		assert.Nil(t, cnv)
+		assert.Nil(t, rt)
+	})
+
+	t.Run("element not registered", func(t *testing.T) {
+		// --- When ---
+		cnv, rt := compositeConverter(DefaultRegistry(), "[]abc")
+
+		// --- Then ---
+		assert.Nil(t, cnv)
+		assert.Nil(t, rt)
+	})
+
+	t.Run("element Go type not known", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+		reg.Register("abc", func(v any) (any, error) { return v, nil })
+
+		// --- When ---
+		cnv, rt := compositeConverter(reg, "[]abc")
+
+		// --- Then ---
+		assert.Nil(t, cnv)
+		assert.Nil(t, rt)
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+		// --- Given ---
+		typ := strings.Repeat("[]", maxTypeDepth) + "int"
+
+		// --- When ---
+		cnv, rt := compositeConverter(DefaultRegistry(), typ)
+
+		// --- Then ---
+		assert.NotNil(t, cnv)
+		assert.Equal(t, typ, rt.String())
+	})
+
+	t.Run("too deep", func(t *testing.T) {
+		// --- Given ---
+		typ := strings.Repeat("[]", maxTypeDepth+1) + "int"
+
+		// --- When ---
+		cnv, rt := compositeConverter(DefaultRegistry(), typ)
+
+		// --- Then ---
+		assert.Nil(t, cnv)
+		assert.Nil(t, rt)
+	})
+
+	t.Run("name too long", func(t *testing.T) {
+		// --- Given ---
+		typ := "map[string]" + strings.Repeat("a", maxTypeLen)
+
+		// --- When ---
+		cnv, rt := compositeConverter(DefaultRegistry(), typ)
+
+		// --- Then ---
+		assert.Nil(t, cnv)
+		assert.Nil(t, rt)
+	})
+
+	t.Run("array too large", func(t *testing.T) {
+		// --- When ---
+		cnv, rt := compositeConverter(DefaultRegistry(), "[4096][3]int64")
+
+		// --- Then ---
+		assert.Nil(t, cnv)
+		assert.Nil(t, rt)
+	})
+
+	t.Run("map key not registered", func(t *testing.T) {
+		// --- When ---
+		cnv, rt := compositeConverter(DefaultRegistry(), "map[abc]int")
+
+		// --- Then ---
+		assert.Nil(t, cnv)
+		assert.Nil(t, rt)
+	})
+
+	t.Run("map key not supported", func(t *testing.T) {
+		// --- When ---
+		cnv, rt := compositeConverter(DefaultRegistry(), "map[float64]int")
+
+		// --- Then ---
+		assert.Nil(t, cnv)
+		assert.Nil(t, rt)
+	})
+}
+
+func Test_compositeEncoder(t *testing.T) {
+	t.Run("slice", func(t *testing.T) {
+		// --- Given ---
+		enc := compositeEncoder(DefaultRegistry(), "[]time.Duration")
+
+		// --- When ---
+		have, err := enc([]time.Duration{time.Second})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"1s"}, have)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		// --- Given ---
+		enc := compositeEncoder(DefaultRegistry(), "map[int][]time.Duration")
+
+		// --- When ---
+		have, err := enc(map[int][]time.Duration{1: {time.Second}})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, map[int]any{1: []any{"1s"}}, have)
+	})
+
+	t.Run("map key with encoder", func(t *testing.T) {
+		// --- Given ---
+		enc := compositeEncoder(DefaultRegistry(), "map[time.Duration]int")
+
+		// --- When ---
+		have, err := enc(map[time.Duration]int{time.Second: 1})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"1s": 1}, have)
+	})
+
+	t.Run("pointer", func(t *testing.T) {
+		// --- Given ---
+		enc := compositeEncoder(DefaultRegistry(), "*time.Duration")
+		v := time.Second
+
+		// --- When ---
+		have, err := enc(&v)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "1s", have)
+	})
+
+	t.Run("not composite", func(t *testing.T) {
+		// --- When ---
+		have := compositeEncoder(DefaultRegistry(), "time.Duration")
+
+		// --- Then ---
+		assert.Nil(t, have)
+	})
+
+	t.Run("element without encoder", func(t *testing.T) {
+		// --- When ---
+		have := compositeEncoder(DefaultRegistry(), "map[string]int")
+
+		// --- Then ---
+		assert.Nil(t, have)
+	})
+}
+
+func Test_listEncoder(t *testing.T) {
+	enc := listEncoder(convert.ToAnyAny(DurationToString))
+
+	t.Run("slice", func(t *testing.T) {
+		// --- When ---
+		have, err := enc([]time.Duration{time.Second, time.Minute})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"1s", "1m0s"}, have)
+	})
+
+	t.Run("array", func(t *testing.T) {
+		// --- When ---
+		have, err := enc([1]time.Duration{time.Second})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []any{"1s"}, have)
+	})
+
+	t.Run("nil slice", func(t *testing.T) {
+		// --- When ---
+		have, err := enc([]time.Duration(nil))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("nil element", func(t *testing.T) {
+		// --- When ---
+		have, err := enc([]any{nil})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []any{nil}, have)
+	})
+
+	t.Run("error - not a slice", func(t *testing.T) {
+		// --- When ---
+		have, err := enc(42)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "invalid type: expected []interface {} got int"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - element", func(t *testing.T) {
+		// --- When ---
+		have, err := enc([]int{42})
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "index 0: invalid type: expected time.Duration got int"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_mapEncoder(t *testing.T) {
+	enc := mapEncoder(nil, convert.ToAnyAny(DurationToString))
+
+	t.Run("map", func(t *testing.T) {
+		// --- When ---
+		have, err := enc(map[string]time.Duration{"A": time.Second})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"A": "1s"}, have)
+	})
+
+	t.Run("nil map", func(t *testing.T) {
+		// --- When ---
+		have, err := enc(map[string]time.Duration(nil))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("nil element", func(t *testing.T) {
+		// --- When ---
+		have, err := enc(map[string]any{"A": nil})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"A": nil}, have)
+	})
+
+	t.Run("key encoder", func(t *testing.T) {
+		// --- Given ---
+		enc := mapEncoder(convert.ToAnyAny(DurationToString), nil)
+
+		// --- When ---
+		have, err := enc(map[time.Duration]int{time.Second: 1})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"1s": 1}, have)
+	})
+
+	t.Run("error - key encoder", func(t *testing.T) {
+		// --- Given ---
+		keyEnc := func(any) (any, error) { return nil, convert.ErrInvValue }
+		enc := mapEncoder(keyEnc, nil)
+
+		// --- When ---
+		have, err := enc(map[int]int{1: 1})
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvValue, err)
+		assert.ErrorEqual(t, "key 1: invalid value", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - key encoder not returning string", func(t *testing.T) {
+		// --- Given ---
+		keyEnc := func(v any) (any, error) { return v, nil }
+		enc := mapEncoder(keyEnc, nil)
+
+		// --- When ---
+		have, err := enc(map[int]int{1: 1})
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "key 1: invalid type: expected string got int"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - not a map", func(t *testing.T) {
+		// --- When ---
+		have, err := enc(42)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - element", func(t *testing.T) {
+		// --- When ---
+		have, err := enc(map[string]int{"A": 42})
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "key A: invalid type: expected time.Duration got int"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_pointerEncoder(t *testing.T) {
+	enc := pointerEncoder(convert.ToAnyAny(DurationToString))
+
+	t.Run("pointer", func(t *testing.T) {
+		// --- Given ---
+		v := time.Second
+
+		// --- When ---
+		have, err := enc(&v)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "1s", have)
+	})
+
+	t.Run("nil pointer", func(t *testing.T) {
+		// --- When ---
+		have, err := enc((*time.Duration)(nil))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - not a pointer", func(t *testing.T) {
+		// --- When ---
+		have, err := enc(time.Second)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "invalid type: expected *interface {} got time.Duration"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - element", func(t *testing.T) {
+		// --- Given ---
+		v := 42
+
+		// --- When ---
+		have, err := enc(&v)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_pointerConverter(t *testing.T) {
+	rt := reflect.TypeFor[*uint64]()
+	cnv := pointerConverter(rt, intConverter(convert.AnyToUint64))
+
+	t.Run("value", func(t *testing.T) {
+		// --- When ---
+		have, err := cnv(json.Number("18446744073709551615"))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		ptr, _ := assert.SameType(t, (*uint64)(nil), have)
+		assert.Equal(t, uint64(math.MaxUint64), *ptr)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		// --- When ---
+		have, err := cnv(nil)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		ptr, _ := assert.SameType(t, (*uint64)(nil), have)
+		assert.Nil(t, ptr)
+	})
+
+	t.Run("already of the pointer type", func(t *testing.T) {
+		// --- Given ---
+		v := uint64(42)
+
+		// --- When ---
+		have, err := cnv(&v)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Same(t, &v, have)
+	})
+
+	t.Run("error - element", func(t *testing.T) {
+		// --- When ---
+		have, err := cnv(json.Number("-1"))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvRange, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_sliceConverter(t *testing.T) {
+	rt := reflect.TypeFor[[]uint64]()
+	cnv := sliceConverter(rt, intConverter(convert.AnyToUint64))
+
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		v := []any{json.Number("1"), json.Number("18446744073709551615")}
+
+		// --- When ---
+		have, err := cnv(v)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{1, math.MaxUint64}, have)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		// --- When ---
+		have, err := cnv([]any{})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{}, have)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		// --- When ---
+		have, err := cnv(nil)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Nil(t, have.([]uint64))
+	})
+
+	t.Run("already of the slice type", func(t *testing.T) {
+		// --- Given ---
+		v := []uint64{1, 2}
+
+		// --- When ---
+		have, err := cnv(v)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, v, have)
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		// --- Given ---
+		rt := reflect.TypeFor[[]byte]()
+		cnv := sliceConverter(rt, intConverter(convert.AnyToByte))
+
+		// --- When ---
+		have, err := cnv("AQI=")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{1, 2}, have)
+	})
+
+	t.Run("error - invalid base64", func(t *testing.T) {
+		// --- Given ---
+		rt := reflect.TypeFor[[]byte]()
+		cnv := sliceConverter(rt, intConverter(convert.AnyToByte))
+
+		// --- When ---
+		have, err := cnv("!!!")
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvFormat, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - not an array", func(t *testing.T) {
+		// --- When ---
+		have, err := cnv("abc")
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		assert.ErrorEqual(t, "invalid type: expected []uint64 got string", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - element", func(t *testing.T) {
+		// --- Given ---
+		v := []any{json.Number("1"), json.Number("-1")}
+
+		// --- When ---
+		have, err := cnv(v)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvRange, err)
+		wMsg := "index 1: value out of range: from int64 to uint64"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_arrayConverter(t *testing.T) {
+	rt := reflect.TypeFor[[2]int8]()
+	cnv := arrayConverter(rt, intConverter(convert.AnyToInt8))
+
+	t.Run("success", func(t *testing.T) {
+		// --- When ---
+		have, err := cnv([]any{json.Number("1"), json.Number("2")})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, [2]int8{1, 2}, have)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		// --- When ---
+		have, err := cnv(nil)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, [2]int8{}, have)
+	})
+
+	t.Run("already of the array type", func(t *testing.T) {
+		// --- When ---
+		have, err := cnv([2]int8{1, 2})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, [2]int8{1, 2}, have)
+	})
+
+	t.Run("error - not an array", func(t *testing.T) {
+		// --- When ---
+		have, err := cnv(map[string]any{})
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - length mismatch", func(t *testing.T) {
+		// --- When ---
+		have, err := cnv([]any{json.Number("1")})
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvValue, err)
+		assert.ErrorEqual(t, "invalid value: expected 2 elements got 1", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - element", func(t *testing.T) {
+		// --- When ---
+		have, err := cnv([]any{json.Number("1"), json.Number("128")})
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvRange, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_mapConverter(t *testing.T) {
+	t.Run("string keys", func(t *testing.T) {
+		// --- Given ---
+		rt := reflect.TypeFor[map[string]time.Duration]()
+		keyCnv := convert.ToAnyAny(convert.StringToString)
+		cnv := convert.ToAnyAny(convert.StringToDuration)
+		cnv = mapConverter(rt, keyCnv, cnv, false)
+
+		// --- When ---
+		have, err := cnv(map[string]any{"A": "1s", "B": "2m"})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := map[string]time.Duration{"A": time.Second, "B": 2 * time.Minute}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("integer keys", func(t *testing.T) {
+		// --- Given ---
+		rt := reflect.TypeFor[map[uint64]string]()
+		keyCnv := intConverter(convert.AnyToUint64)
+		str := convert.ToAnyAny(convert.StringToString)
+		cnv := mapConverter(rt, keyCnv, str, false)
+
+		// --- When ---
+		have, err := cnv(map[string]any{"18446744073709551615": "A"})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, map[uint64]string{math.MaxUint64: "A"}, have)
+	})
+
+	t.Run("text keys", func(t *testing.T) {
+		// --- Given ---
+		rt := reflect.TypeFor[map[time.Duration]string]()
+		keyCnv := convert.ToAnyAny(convert.StringToDuration)
+		str := convert.ToAnyAny(convert.StringToString)
+		cnv := mapConverter(rt, keyCnv, str, true)
+
+		// --- When ---
+		have, err := cnv(map[string]any{"1s": "A"})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, map[time.Duration]string{time.Second: "A"}, have)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		// --- Given ---
+		rt := reflect.TypeFor[map[string]string]()
+		str := convert.ToAnyAny(convert.StringToString)
+		cnv := mapConverter(rt, str, str, false)
+
+		// --- When ---
+		have, err := cnv(nil)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Nil(t, have.(map[string]string))
+	})
+
+	t.Run("already of the map type", func(t *testing.T) {
+		// --- Given ---
+		rt := reflect.TypeFor[map[string]string]()
+		str := convert.ToAnyAny(convert.StringToString)
+		cnv := mapConverter(rt, str, str, false)
+		v := map[string]string{"A": "a"}
+
+		// --- When ---
+		have, err := cnv(v)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, v, have)
+	})
+
+	t.Run("error - not an object", func(t *testing.T) {
+		// --- Given ---
+		rt := reflect.TypeFor[map[string]string]()
+		str := convert.ToAnyAny(convert.StringToString)
+		cnv := mapConverter(rt, str, str, false)
+
+		// --- When ---
+		have, err := cnv([]any{})
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - key", func(t *testing.T) {
+		// --- Given ---
+		rt := reflect.TypeFor[map[int8]string]()
+		keyCnv := intConverter(convert.AnyToInt8)
+		str := convert.ToAnyAny(convert.StringToString)
+		cnv := mapConverter(rt, keyCnv, str, false)
+
+		// --- When ---
+		have, err := cnv(map[string]any{"128": "A"})
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvRange, err)
+		wMsg := `key "128": value out of range: from int64 to int8`
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - element", func(t *testing.T) {
+		// --- Given ---
+		rt := reflect.TypeFor[map[string]int8]()
+		keyCnv := convert.ToAnyAny(convert.StringToString)
+		cnv := mapConverter(rt, keyCnv, intConverter(convert.AnyToInt8), false)
+
+		// --- When ---
+		have, err := cnv(map[string]any{"A": json.Number("128")})
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvRange, err)
+		wMsg := `key "A": value out of range: from int64 to int8`
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_setElem(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		dst := reflect.New(reflect.TypeFor[uint]()).Elem()
+
+		// --- When ---
+		err := setElem(dst, intConverter(convert.AnyToUint), json.Number("42"))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, uint(42), dst.Interface())
+	})
+
+	t.Run("nil result sets zero value", func(t *testing.T) {
+		// --- Given ---
+		dst := reflect.New(reflect.TypeFor[any]()).Elem()
+		dst.Set(reflect.ValueOf(42))
+
+		// --- When ---
+		err := setElem(dst, NilConverter, nil)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Nil(t, dst.Interface())
+	})
+
+	t.Run("error - not assignable", func(t *testing.T) {
+		// --- Given ---
+		dst := reflect.New(reflect.TypeFor[uint]()).Elem()
+		cnv := func(any) (any, error) { return "abc", nil }
+
+		// --- When ---
+		err := setElem(dst, cnv, json.Number("42"))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		assert.ErrorEqual(t, "invalid type: expected uint got string", err)
+	})
+}
+
+func Test_isMapKey(t *testing.T) {
+	assert.True(t, isMapKey(reflect.TypeFor[string]()))
+	assert.True(t, isMapKey(reflect.TypeFor[int]()))
+	assert.True(t, isMapKey(reflect.TypeFor[uint64]()))
+	assert.True(t, isMapKey(reflect.TypeFor[time.Duration]()))
+	assert.False(t, isMapKey(reflect.TypeFor[float64]()))
+	assert.False(t, isMapKey(reflect.TypeFor[bool]()))
+	assert.False(t, isMapKey(reflect.TypeFor[[2]int]()))
+}
+
+func Test_Value_round_trip_composite_tabular(t *testing.T) {
+	tim := time.Date(2000, 1, 2, 3, 4, 5, 600000000, time.UTC)
+
+	tt := []struct {
+		testN string
+
+		val *Value
+	}{
+		{"slice", New([]uint64{1, math.MaxUint64})},
+		{"empty slice", New([]int{})},
+		{"nil slice", New([]int(nil))},
+		{"bytes", New([]byte{1, 2, 3})},
+		{"array", New([3]int8{-1, 0, 1})},
+		{"array of bytes", New([2]byte{1, 2})},
+		{"slice of slices", New([][]time.Time{{tim}, nil, {}})},
+		{"map", New(map[string]time.Time{"A": tim})},
+		{"map of durations", New(map[string]time.Duration{"A": time.Second})},
+		{"slice of durations", New([]time.Duration{time.Second, time.Hour})},
+		{"array of durations", New([1]time.Duration{time.Second})},
+		{"map integer keys", New(map[int64]string{math.MinInt64: "A"})},
+		{"map duration keys", New(map[time.Duration]string{time.Second: "A"})},
+		{"map of duration keys", New(map[time.Duration]int{time.Hour: 1})},
+		{"nil map", New(map[string]int(nil))},
+		{"map of slices", New(map[uint8][]float64{1: {4.2}})},
+		{"slice of maps", New([]map[string]bool{{"A": true}, nil})},
+		{"slice of arrays", New([][2]string{{"A", "B"}})},
+		{"pointer", New(ptr(uint64(math.MaxUint64)))},
+		{"nil pointer", New[*int](nil)},
+		{"pointer to pointer", New(ptr(ptr(time.Second)))},
+		{"pointer to slice", New(ptr([]time.Duration{time.Second}))},
+		{"slice of pointers", New([]*int{ptr(1), nil})},
+		{"map of pointers", New(map[string]*time.Time{"A": &tim, "B": nil})},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- Given ---
+			data := must.Value(json.Marshal(tc.val))
+			have := &Value{}
+
+			// --- When ---
+			err := json.Unmarshal(data, have)
+
+			// --- Then ---
+			assert.NoError(t, err)
+			assert.Equal(t, tc.val.typ, have.typ)
+			assert.Equal(t, tc.val.val, have.val)
+			assert.SameType(t, tc.val.val, have.val)
+		})
+	}
+}
+
+func Test_Value_UnmarshalJSON_composite_errors(t *testing.T) {
+	t.Run("error - element out of range", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "[]uint8", "value": [1, 256]}`
+		val := &Value{}
+
+		// --- When ---
+		err := val.UnmarshalJSON([]byte(data))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvRange, err)
+		wMsg := "jsontype: index 1: value out of range: from int64 to uint8"
+		assert.ErrorEqual(t, wMsg, err)
+	})
+
+	t.Run("error - element type not registered", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "[]abc", "value": []}`
+		val := &Value{}
+
+		// --- When ---
+		err := val.UnmarshalJSON([]byte(data))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		assert.ErrorEqual(t, "unsupported type: []abc", err)
+	})
+
+	t.Run("error - array length overflow", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type":"[9223372036854775807]int64","value":[]}`
+
+		// --- When ---
+		have, err := UnmarshalValue([]byte(data))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - array too long", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type":"[100000000]int","value":null}`
+
+		// --- When ---
+		have, err := UnmarshalValue([]byte(data))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - array too large", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type":"[4096][4096]int","value":null}`
+
+		// --- When ---
+		have, err := UnmarshalValue([]byte(data))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - type name too deep", func(t *testing.T) {
+		// --- Given ---
+		typ := strings.Repeat("[]", 40_000) + "int"
+		data := `{"type":"` + typ + `","value":null}`
+
+		// --- When ---
+		have, err := UnmarshalValue([]byte(data))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - type name too long", func(t *testing.T) {
+		// --- Given ---
+		typ := "[]map[string]" + strings.Repeat("a", maxTypeLen)
+		data := `{"type":"` + typ + `","value":null}`
+
+		// --- When ---
+		have, err := UnmarshalValue([]byte(data))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - array length mismatch", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type":"[4096]int","value":[1]}`
+
+		// --- When ---
+		have, err := UnmarshalValue([]byte(data))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvValue, err)
+		assert.Nil(t, have)
+	})
+}
+
+// ptr returns a pointer to the value.
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// ErrConflict is returned when registering an already registered type name.
+var ErrConflict = errors.New("type name already registered")
+
+// ConflictError represents a rejected registration of an already registered
+// type name. It wraps [ErrConflict].
+type ConflictError struct {
+	Name     string // The type name.
+	Previous string // Call site of the existing registration.
+	Current  string // Call site of the rejected registration.
+}
+
+func (e *ConflictError) Error() string {
+	format := "%s: %s (registered at %s, rejected at %s)"
+	return fmt.Sprintf(format, ErrConflict, e.Name, e.Previous, e.Current)
+}
+
+func (e *ConflictError) Unwrap() error { return ErrConflict }
+
+// SetStrictRegister turns on the strict registration policy of the registry,
+// the same [WithStrictRegister] turns on for new registries. From now on,
+// registering an already registered type name panics with [ConflictError].
+//
+// Registrations done before, which replaced already registered converters,
+// are returned as [ConflictError] instances joined with [errors.Join], one
+// for the first replacement of each type name, in lexical order of the names.
+// Returns nil when there were none.
+func (reg *Registry) SetStrictRegister() error {
+	reg.mx.Lock()
+	defer reg.mx.Unlock()
+	reg.strict.Store(true)
+	var errs []error
+	cfl := reg.snap.Load().cfl
+	for _, name := range slices.Sorted(maps.Keys(cfl)) {
+		errs = append(errs, cfl[name])
+	}
+	return errors.Join(errs...)
+}
+
+// pkgPath is the import path of the package.
+var pkgPath = reflect.TypeFor[Registry]().PkgPath()
+
+// builtinSite is the call site recorded for registrations done by
+// [DefaultRegistry].
+const builtinSite = "jsontype.DefaultRegistry"
+
+// callSite returns the "file:line" location of the code which called the
+// package registration function. Frames in the package source files are
+// skipped, so the location points to the caller of, for example,
+// [RegisterStruct], not to the package internals. Registrations done by
+// [DefaultRegistry] are reported as [builtinSite].
+func callSite() string {
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:]) // Skip runtime.Callers and callSite.
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if f.Function == pkgPath+".DefaultRegistry" {
+			return builtinSite
+		}
+		internal := strings.HasPrefix(f.Function, pkgPath+".") &&
+			!strings.HasSuffix(f.File, "_test.go")
+		if !internal {
+			return fmt.Sprintf("%s:%d", f.File, f.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"testing"
+
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
+)
+
+func Test_ConflictError(t *testing.T) {
+	t.Run("Error", func(t *testing.T) {
+		// --- Given ---
+		err := &ConflictError{
+			Name:     "money",
+			Previous: "a.go:1",
+			Current:  "b.go:2",
+		}
+
+		// --- When ---
+		have := err.Error()
+
+		// --- Then ---
+		want := "type name already registered: money " +
+			"(registered at a.go:1, rejected at b.go:2)"
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("Unwrap", func(t *testing.T) {
+		// --- Given ---
+		err := &ConflictError{Name: "money"}
+
+		// --- When ---
+		have := err.Unwrap()
+
+		// --- Then ---
+		assert.Same(t, ErrConflict, have)
+	})
+}
+
+func Test_Registry_SetStrictRegister(t *testing.T) {
+	t.Run("no conflicts", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+
+		// --- When ---
+		err := reg.SetStrictRegister()
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.True(t, reg.strict.Load())
+	})
+
+	t.Run("conflicts before", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := DefaultRegistry()
+		reg.Register("money", cnv)
+		reg.Register("money", cnv)
+		reg.Register(Int, cnv)
+
+		// --- When ---
+		err := reg.SetStrictRegister()
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrConflict, err)
+		var e *ConflictError
+		assert.ErrorAs(t, &e, err)
+		assert.Equal(t, Int, e.Name)
+		assert.Equal(t, builtinSite, e.Previous)
+		assert.Regexp(t, `/conflict_test\.go:\d+$`, e.Current)
+		assert.ErrorContain(t, "money (registered at ", err)
+		assert.Len(t, 2, reg.snap.Load().cfl)
+	})
+
+	t.Run("first conflict of each name", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+		reg.Register("money", cnv)
+		reg.Register("money", cnv)
+		want := reg.snap.Load().cfl["money"]
+
+		// --- When ---
+		for range 100 {
+			reg.Register("money", cnv)
+		}
+
+		// --- Then ---
+		assert.Len(t, 1, reg.snap.Load().cfl)
+		assert.Same(t, want, reg.snap.Load().cfl["money"])
+		var e *ConflictError
+		assert.ErrorAs(t, &e, reg.SetStrictRegister())
+		assert.Same(t, want, e)
+	})
+
+	t.Run("conflicts after panic", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := DefaultRegistry()
+		must.Nil(reg.SetStrictRegister())
+
+		// --- When ---
+		msg := assert.PanicMsg(t, func() { reg.Register(Int, cnv) })
+
+		// --- Then ---
+		assert.Contain(t, "type name already registered: int", *msg)
+		assert.Len(t, 0, reg.snap.Load().cfl)
+	})
+
+	t.Run("struct conflicts after return error", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+		must.Nil(RegisterStruct[TCircle]("shape", WithRegistry(reg)))
+		must.Nil(reg.SetStrictRegister())
+
+		// --- When ---
+		err := RegisterStruct[TSquare]("shape", WithRegistry(reg))
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrConflict, err)
+	})
+
+	t.Run("clone keeps the policy", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+		must.Nil(reg.SetStrictRegister())
+
+		// --- When ---
+		have := reg.Clone()
+
+		// --- Then ---
+		assert.True(t, have.strict.Load())
+	})
+}
+
+func Test_callSite(t *testing.T) {
+	t.Run("caller", func(t *testing.T) {
+		// --- When ---
+		have := callSite()
+
+		// --- Then ---
+		assert.Regexp(t, `/conflict_test\.go:\d+$`, have)
+	})
+
+	t.Run("package internals are skipped", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry(WithStrictRegister())
+		assert.NoError(t, RegisterStruct[TCircle]("circle", WithRegistry(reg)))
+
+		// --- When ---
+		err := RegisterStruct[TCircle]("circle", WithRegistry(reg))
+
+		// --- Then ---
+		var e *ConflictError
+		assert.ErrorAs(t, &e, err)
+		assert.Regexp(t, `/conflict_test\.go:\d+$`, e.Previous)
+		assert.Regexp(t, `/conflict_test\.go:\d+$`, e.Current)
+		assert.NotEqual(t, e.Previous, e.Current)
+	})
+
+	t.Run("default registry", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+
+		// --- When ---
+		have := reg.snap.Load().pos[Int]
+
+		// --- Then ---
+		assert.Equal(t, builtinSite, have)
+	})
+}
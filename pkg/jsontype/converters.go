@@ -4,7 +4,10 @@
 package jsontype
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/ctx42/convert/pkg/convert"
 )
@@ -18,3 +21,76 @@ func NilConverter(value any) (any, error) {
 	}
 	return nil, nil
 }
+
+// intConverter returns a converter to an integer type. Values of type
+// [json.Number] are parsed as int64 or uint64 before the conversion, so
+// integers outside the float64 safe range are converted without loss. All
+// other values are passed to cnv as they are.
+func intConverter[T any](
+	cnv func(any, ...convert.Option) (T, error),
+) convert.AnyToAny {
+
+	return func(value any) (any, error) {
+		if num, ok := value.(json.Number); ok {
+			var err error
+			if value, err = parseInteger(num); err != nil {
+				return nil, err
+			}
+		}
+		return cnv(value)
+	}
+}
+
+// floatConverter returns a converter to a floating-point type. Values of type
+// [json.Number] are parsed as float64 before the conversion. All other values
+// are passed to cnv as they are.
+func floatConverter[T any](
+	cnv func(any, ...convert.Option) (T, error),
+) convert.AnyToAny {
+
+	return func(value any) (any, error) {
+		if num, ok := value.(json.Number); ok {
+			var err error
+			if value, err = parseFloat(num); err != nil {
+				return nil, err
+			}
+		}
+		return cnv(value)
+	}
+}
+
+// parseInteger parses [json.Number] as int64. When it doesn't fit, it parses
+// it as uint64 and as a last resort as float64 (exponent or fraction notation).
+func parseInteger(num json.Number) (any, error) {
+	if i64, err := strconv.ParseInt(string(num), 10, 64); err == nil {
+		return i64, nil
+	}
+	if u64, err := strconv.ParseUint(string(num), 10, 64); err == nil {
+		return u64, nil
+	}
+	return parseFloat(num)
+}
+
+// parseFloat parses [json.Number] as float64.
+func parseFloat(num json.Number) (any, error) {
+	f64, err := strconv.ParseFloat(string(num), 64)
+	if err != nil {
+		err = convert.NewError(convert.ErrInvValue, "json.Number", "float64")
+		return nil, err
+	}
+	return f64, nil
+}
+
+// DurationToString encodes [time.Duration] in the format accepted by
+// [time.ParseDuration].
+func DurationToString(value time.Duration) (string, error) {
+	return value.String(), nil
+}
+
+// TimeToString returns an encoder which formats [time.Time] according to the
+// specified layout.
+func TimeToString(layout string) func(value time.Time) (string, error) {
+	return func(value time.Time) (string, error) {
+		return value.Format(layout), nil
+	}
+}
@@ -4,7 +4,10 @@
 package jsontype
 
 import (
+	"encoding/json"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/ctx42/convert/pkg/convert"
 	"github.com/ctx42/testing/pkg/assert"
@@ -31,3 +34,198 @@ func Test_NilConverter(t *testing.T) {
 		assert.Nil(t, have)
 	})
 }
+
+func Test_intConverter(t *testing.T) {
+	t.Run("json.Number int64", func(t *testing.T) {
+		// --- Given ---
+		cnv := intConverter(convert.AnyToInt64)
+
+		// --- When ---
+		have, err := cnv(json.Number("-9223372036854775808"))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, int64(math.MinInt64), have)
+	})
+
+	t.Run("json.Number uint64", func(t *testing.T) {
+		// --- Given ---
+		cnv := intConverter(convert.AnyToUint64)
+
+		// --- When ---
+		have, err := cnv(json.Number("18446744073709551615"))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(math.MaxUint64), have)
+	})
+
+	t.Run("json.Number exponent", func(t *testing.T) {
+		// --- Given ---
+		cnv := intConverter(convert.AnyToUint16)
+
+		// --- When ---
+		have, err := cnv(json.Number("4.2e1"))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, uint16(42), have)
+	})
+
+	t.Run("float64", func(t *testing.T) {
+		// --- Given ---
+		cnv := intConverter(convert.AnyToInt8)
+
+		// --- When ---
+		have, err := cnv(42.0)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, int8(42), have)
+	})
+
+	t.Run("Go integer", func(t *testing.T) {
+		// --- Given ---
+		cnv := intConverter(convert.AnyToUint)
+
+		// --- When ---
+		have, err := cnv(uint(42))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, uint(42), have)
+	})
+
+	t.Run("error - out of range", func(t *testing.T) {
+		// --- Given ---
+		cnv := intConverter(convert.AnyToInt64)
+
+		// --- When ---
+		_, err := cnv(json.Number("9223372036854775808"))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvRange, err)
+		assert.ErrorEqual(t, "value out of range: from uint64 to int64", err)
+	})
+
+	t.Run("error - fraction", func(t *testing.T) {
+		// --- Given ---
+		cnv := intConverter(convert.AnyToInt)
+
+		// --- When ---
+		_, err := cnv(json.Number("4.2"))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrFraction, err)
+	})
+
+	t.Run("error - invalid number", func(t *testing.T) {
+		// --- Given ---
+		cnv := intConverter(convert.AnyToInt)
+
+		// --- When ---
+		have, err := cnv(json.Number("abc"))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvValue, err)
+		assert.ErrorEqual(t, "invalid value: from json.Number to float64", err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_floatConverter(t *testing.T) {
+	t.Run("json.Number", func(t *testing.T) {
+		// --- Given ---
+		cnv := floatConverter(convert.AnyToFloat64)
+
+		// --- When ---
+		have, err := cnv(json.Number("4.2"))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, 4.2, have)
+	})
+
+	t.Run("float64", func(t *testing.T) {
+		// --- Given ---
+		cnv := floatConverter(convert.AnyToFloat32)
+
+		// --- When ---
+		have, err := cnv(42.0)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, float32(42), have)
+	})
+
+	t.Run("error - invalid number", func(t *testing.T) {
+		// --- Given ---
+		cnv := floatConverter(convert.AnyToFloat64)
+
+		// --- When ---
+		have, err := cnv(json.Number("abc"))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvValue, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_parseInteger(t *testing.T) {
+	t.Run("int64", func(t *testing.T) {
+		// --- When ---
+		have, err := parseInteger("-42")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, int64(-42), have)
+	})
+
+	t.Run("uint64", func(t *testing.T) {
+		// --- When ---
+		have, err := parseInteger("18446744073709551615")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(math.MaxUint64), have)
+	})
+
+	t.Run("float64", func(t *testing.T) {
+		// --- When ---
+		have, err := parseInteger("1e3")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, 1000.0, have)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		// --- When ---
+		have, err := parseInteger("abc")
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvValue, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_DurationToString(t *testing.T) {
+	// --- When ---
+	have, err := DurationToString(90 * time.Second)
+
+	// --- Then ---
+	assert.NoError(t, err)
+	assert.Equal(t, "1m30s", have)
+}
+
+func Test_TimeToString(t *testing.T) {
+	// --- Given ---
+	tim := time.Date(2000, 1, 2, 3, 4, 5, 600000000, time.UTC)
+
+	// --- When ---
+	have, err := TimeToString(time.RFC3339Nano)(tim)
+
+	// --- Then ---
+	assert.NoError(t, err)
+	assert.Equal(t, "2000-01-02T03:04:05.6Z", have)
+}
@@ -2,8 +2,13 @@ package jsontype_test
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"math"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/ctx42/convert/pkg/convert"
@@ -55,7 +60,7 @@ func ExampleValue_UnmarshalJSON_safe() {
 
 	fmt.Println(err)
 	// Output:
-	// jsontype: value out of range: from float64 to uint8
+	// jsontype: value out of range: from int64 to uint8
 }
 
 func ExampleRegister_custom() {
@@ -94,3 +99,451 @@ func ExampleFromMap() {
 	fmt.Printf("%v (%T)\n", val.GoValue(), val.GoValue())
 	// Output: 42 (uint)
 }
+
+func ExampleValue_UnmarshalJSON_composite() {
+	data := []byte(`{"type": "map[string][]uint64", "value": {"A": [1, 2]}}`)
+
+	gType := &jsontype.Value{}
+	_ = json.Unmarshal(data, gType)
+
+	fmt.Printf("%[1]v (%[1]T)\n", gType.GoValue())
+	// Output:
+	// map[A:[1 2]] (map[string][]uint64)
+}
+
+func ExampleTyped() {
+	type Message struct {
+		ID jsontype.Typed[uint64] `json:"id"`
+	}
+
+	msg := Message{ID: jsontype.NewTyped(uint64(18446744073709551615))}
+	data, _ := json.Marshal(msg)
+
+	got := Message{}
+	_ = json.Unmarshal(data, &got)
+
+	fmt.Printf("  marshalled: %s\n", string(data))
+	fmt.Printf("unmarshalled: %[1]v (%[1]T)\n", got.ID.Get())
+	// Output:
+	// marshalled: {"id":{"type":"uint64","value":18446744073709551615}}
+	// unmarshalled: 18446744073709551615 (uint64)
+}
+
+func ExampleFromMap_decoded() {
+	// Message decoded from JSON into a generic map.
+	data := []byte(`{"type": "uint", "value": 42}`)
+	m := make(map[string]any)
+	_ = json.Unmarshal(data, &m)
+
+	val, err := jsontype.FromMap(m)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("%v (%T)\n", val.GoValue(), val.GoValue())
+	// Output: 42 (uint)
+}
+
+func ExampleRegisterEncoder() {
+	// Custom type named "minutes" representing duration in minutes.
+	reg := jsontype.DefaultRegistry()
+
+	// Decoder from JSON number to time.Duration.
+	cnv := func(value float64) (time.Duration, error) {
+		return time.Duration(value) * time.Minute, nil
+	}
+	reg.Register("minutes", convert.ToAnyAny(cnv))
+
+	// Encoder from time.Duration to JSON number.
+	enc := func(value time.Duration) (float64, error) {
+		return value.Minutes(), nil
+	}
+	reg.RegisterEncoder("minutes", convert.ToAnyAny(enc))
+
+	data := []byte(`{"type": "minutes", "value": 42}`)
+	gType := &jsontype.Value{}
+	_ = jsontype.Unmarshal(reg, data, gType)
+	data, _ = jsontype.Marshal(reg, gType)
+
+	fmt.Printf("unmarshalled: %[1]v (%[1]T)\n", gType.GoValue())
+	fmt.Printf("  marshalled: %s\n", string(data))
+	// Output:
+	// unmarshalled: 42m0s (time.Duration)
+	//   marshalled: {"type":"minutes","value":42}
+}
+
+func ExampleRegisterStruct() {
+	type Event struct {
+		ID  uint64        `json:"id"`
+		At  time.Time     `json:"at"`
+		TTL time.Duration `json:"ttl"`
+	}
+
+	reg := jsontype.DefaultRegistry()
+	err := jsontype.RegisterStruct[Event]("event", jsontype.WithRegistry(reg))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	data := []byte(`{
+		"type": "event",
+		"value": {
+			"id": 18446744073709551615,
+			"at": "2000-01-02T03:04:05Z",
+			"ttl": "1m0s"
+		}
+	}`)
+
+	gType := &jsontype.Value{}
+	if err = jsontype.Unmarshal(reg, data, gType); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("%+v\n", gType.GoValue())
+	// Output:
+	// {ID:18446744073709551615 At:2000-01-02 03:04:05 +0000 UTC TTL:1m0s}
+}
+
+// Shape is an interface used in examples.
+type Shape interface{ Area() float64 }
+
+// Circle is a [Shape] implementation used in examples.
+type Circle struct {
+	R float64 `json:"r"`
+}
+
+func (c Circle) Area() float64 { return 3 * c.R * c.R }
+
+// Square is a [Shape] implementation used in examples.
+type Square struct {
+	A float64 `json:"a"`
+}
+
+func (s Square) Area() float64 { return s.A * s.A }
+
+func ExampleRegisterInterface() {
+	reg := jsontype.DefaultRegistry()
+	_ = jsontype.RegisterStruct[Circle]("circle", jsontype.WithRegistry(reg))
+	_ = jsontype.RegisterStruct[Square]("square", jsontype.WithRegistry(reg))
+
+	impls := []string{"circle", "square"}
+	err := jsontype.RegisterInterface[Shape](
+		"shape",
+		impls,
+		jsontype.WithRegistry(reg),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	data := []byte(`[
+		{"type": "circle", "value": {"r": 1}},
+		{"type": "square", "value": {"a": 2}}
+	]`)
+
+	opt := jsontype.WithRegistry(reg)
+	shapes, err := jsontype.UnmarshalSlice[Shape](data, opt)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, shape := range shapes {
+		fmt.Printf("%T %v\n", shape, shape.Area())
+	}
+	// Output:
+	// jsontype_test.Circle 3
+	// jsontype_test.Square 4
+}
+
+func ExampleMap() {
+	doc := jsontype.Map{
+		"id":   uint64(math.MaxUint64),
+		"name": "test",
+		"tags": []any{"a", time.Second},
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(data))
+
+	var have jsontype.Map
+	if err = json.Unmarshal(data, &have); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%T %T\n", have["id"], have["tags"].([]any)[1])
+	// Output:
+	// {"id":{"type":"uint64","value":18446744073709551615},"name":"test","tags":["a",{"type":"time.Duration","value":"1s"}]}
+	// uint64 time.Duration
+}
+
+func ExampleAnnotated() {
+	doc := jsontype.Annotated{
+		"id":   uint64(42),
+		"tags": []any{"a", time.Second},
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(data))
+
+	var have jsontype.Annotated
+	if err = json.Unmarshal(data, &have); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%T %T\n", have["id"], have["tags"].([]any)[1])
+	// Output:
+	// {"$types":{"/id":"uint64","/tags/1":"time.Duration"},"id":42,"tags":["a","1s"]}
+	// uint64 time.Duration
+}
+
+func ExampleDecodeWithSchema() {
+	data := []byte(`{"id": 18446744073709551615, "items": [{"ttl": "1s"}]}`)
+	schema := jsontype.Schema{
+		"id":           "uint64",
+		"/items/0/ttl": "time.Duration",
+	}
+
+	reg := jsontype.DefaultRegistry()
+	doc, err := jsontype.DecodeWithSchema(reg, schema, data)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	m := doc.(map[string]any)
+	item := m["items"].([]any)[0].(map[string]any)
+	fmt.Printf("%T %v\n", m["id"], m["id"])
+	fmt.Printf("%T %v\n", item["ttl"], item["ttl"])
+	// Output:
+	// uint64 18446744073709551615
+	// time.Duration 1s
+}
+
+func ExampleInfer() {
+	data := []byte(`{"id": 42, "at": "2000-01-02T03:04:05Z", "ratio": 0.5}`)
+
+	doc, err := jsontype.Infer(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, key := range []string{"id", "at", "ratio"} {
+		val := doc.(map[string]any)[key].(*jsontype.Value)
+		fmt.Println(key, val.GoTypeName())
+	}
+	// Output:
+	// id int64
+	// at time.Time
+	// ratio float64
+}
+
+func ExampleWithUnknown() {
+	data := []byte(`{"type":"money","value":{"amount":"1.50","currency":"EUR"}}`)
+
+	val, err := jsontype.UnmarshalValue(data, jsontype.WithUnknown())
+	if err != nil {
+		log.Fatal(err)
+	}
+	u := val.GoValue().(*jsontype.UnknownValue)
+	fmt.Println(u.GoTypeName(), string(u.Raw()))
+
+	out, err := json.Marshal(val)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(out))
+	// Output:
+	// money {"amount":"1.50","currency":"EUR"}
+	// {"type":"money","value":{"amount":"1.50","currency":"EUR"}}
+}
+
+func ExampleDecoder() {
+	data := `{"type": "uint64", "value": 18446744073709551615}
+		{"type": "time.Duration", "value": "1s"}`
+
+	dec := jsontype.NewDecoder(strings.NewReader(data))
+	enc := jsontype.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	for {
+		val := &jsontype.Value{}
+		if err := dec.Decode(val); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			log.Fatal(err)
+		}
+		if err := enc.Encode(val); err != nil {
+			log.Fatal(err)
+		}
+	}
+	// Output:
+	// {
+	//   "type": "uint64",
+	//   "value": 18446744073709551615
+	// }
+	// {
+	//   "type": "time.Duration",
+	//   "value": "1s"
+	// }
+}
+
+func ExampleReadNDJSON() {
+	data := `{"type": "uint8", "value": 1}
+{"type": "uint8", "value": 256}
+{"type": "time.Duration", "value": "1s"}`
+
+	for val, err := range jsontype.ReadNDJSON(strings.NewReader(data)) {
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		fmt.Printf("%T %v\n", val.GoValue(), val.GoValue())
+	}
+	// Output:
+	// uint8 1
+	// line 2: jsontype: value out of range: from int64 to uint8
+	// time.Duration 1s
+}
+
+func ExampleReadArray() {
+	data := `{
+		"meta": {"count": 2},
+		"data": [
+			{"type": "uint64", "value": 18446744073709551615},
+			{"type": "time.Duration", "value": "1s"}
+		]
+	}`
+
+	for val, err := range jsontype.ReadArray(strings.NewReader(data), "/data") {
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%T %v\n", val.GoValue(), val.GoValue())
+	}
+	// Output:
+	// uint64 18446744073709551615
+	// time.Duration 1s
+}
+
+func ExampleWithLazy() {
+	data := []byte(`{"type": "uint8", "value": 256}`)
+
+	val, err := jsontype.UnmarshalValue(data, jsontype.WithLazy())
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(val.GoTypeName())
+
+	_, err = val.Decode()
+	fmt.Println(err)
+	// Output:
+	// uint8
+	// jsontype: value out of range: from int64 to uint8
+}
+
+func ExampleAppendJSON() {
+	vals := []*jsontype.Value{
+		jsontype.New(uint8(42)),
+		jsontype.New(1500 * time.Millisecond),
+	}
+
+	reg := jsontype.DefaultRegistry()
+	buf := make([]byte, 0, 128)
+	for _, val := range vals {
+		var err error
+		if buf, err = jsontype.AppendJSON(buf[:0], reg, val); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(string(buf))
+	}
+	// Output:
+	// {"type":"uint8","value":42}
+	// {"type":"time.Duration","value":"1.5s"}
+}
+
+func ExampleRegistry_Names() {
+	reg := jsontype.DefaultRegistry()
+	reg.Unregister(jsontype.Nil)
+
+	fmt.Println(reg.Len(), reg.Has(jsontype.Nil))
+	fmt.Println(reg.Names()[:3])
+	// Output:
+	// 18 false
+	// [bool byte float32]
+}
+
+func ExampleRegistry_RegisterStrict() {
+	reg := jsontype.DefaultRegistry()
+	cnv := func(v any) (any, error) { return v, nil }
+
+	err := reg.RegisterStrict(jsontype.Int, cnv)
+	fmt.Println(errors.Is(err, jsontype.ErrConflict))
+
+	var e *jsontype.ConflictError
+	if errors.As(err, &e) {
+		fmt.Println(e.Name, e.Previous)
+	}
+	// Output:
+	// true
+	// int jsontype.DefaultRegistry
+}
+
+func ExampleRegistry_Freeze() {
+	reg := jsontype.DefaultRegistry()
+	reg.Freeze()
+
+	cnv := func(v any) (any, error) { return v, nil }
+	err := reg.RegisterStrict("money", cnv)
+
+	fmt.Println(reg.IsFrozen())
+	fmt.Println(err)
+	// Output:
+	// true
+	// registry is frozen
+}
+
+func ExampleWithParent() {
+	base := jsontype.DefaultRegistry()
+	base.Freeze()
+
+	reg := jsontype.NewRegistry(jsontype.WithParent(base))
+	cnv := func(v float64) (time.Duration, error) {
+		return time.Duration(v * float64(time.Second)), nil
+	}
+	reg.Register("seconds", convert.ToAnyAny(cnv))
+	reg.Block(jsontype.Time)
+
+	val := &jsontype.Value{}
+	data := `{"type":"seconds","value":1.5}`
+	if err := jsontype.Unmarshal(reg, []byte(data), val); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(val.GoValue())
+	fmt.Println(reg.Has(jsontype.Int), reg.Has(jsontype.Time))
+	fmt.Println(base.Has("seconds"))
+	// Output:
+	// 1.5s
+	// true false
+	// false
+}
+
+func ExampleWithAllowedTypes() {
+	opt := jsontype.WithAllowedTypes(jsontype.Int, jsontype.String)
+
+	data := `{"type":"int","value":42}`
+	val, err := jsontype.UnmarshalValue([]byte(data), opt)
+	fmt.Println(val.GoValue(), err)
+
+	data = `{"type":"[]int","value":[42]}`
+	_, err = jsontype.UnmarshalValue([]byte(data), opt)
+	fmt.Println(errors.Is(err, jsontype.ErrTypeNotAllowed))
+	fmt.Println(err)
+	// Output:
+	// 42 <nil>
+	// true
+	// type not allowed: []int
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ctx42/convert/pkg/convert"
+)
+
+// ErrFrozen is returned when changing a frozen [Registry].
+var ErrFrozen = errors.New("registry is frozen")
+
+// maxCached is the maximum number of composite type lookups cached by a
+// frozen [Registry] (for converters and encoders, separately).
+const maxCached = 1024
+
+// Freeze freezes the registry. The converters, Go types and encoders of a
+// frozen registry cannot be changed:
+//
+//   - [Registry.RegisterStrict] returns [ErrFrozen],
+//   - [RegisterStruct] and [RegisterInterface] return [ErrFrozen],
+//   - [Registry.MustRegister], [Registry.Register], [Registry.RegisterType],
+//     [Registry.RegisterNumber], [Registry.RegisterEncoder],
+//     [Registry.Unregister] and [Registry.Block] panic with [ErrFrozen].
+//
+// The methods which cannot return an error panic for all registries, not
+// only for the ones created with [WithStrictRegister], so a late
+// registration is never silently dropped. Use [Registry.RegisterStrict] to
+// get [ErrFrozen] as an error.
+//
+// Lookups in a frozen registry cache converters and encoders built for
+// composite type names, so they are built only once. Freezing a frozen
+// registry is a no-op.
+func (reg *Registry) Freeze() {
+	_ = reg.update(func(snap *snapshot) error {
+		snap.cache = &lookupCache{}
+		return nil
+	})
+}
+
+// IsFrozen returns true if the registry is frozen.
+func (reg *Registry) IsFrozen() bool { return reg.snap.Load().cache != nil }
+
+// check handles the error returned by the registration which cannot report
+// it. Conflicts and changes of a frozen registry panic, regardless of the
+// strict registration policy.
+func (reg *Registry) check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// lookupCache caches composite type lookups of a frozen [Registry].
+type lookupCache struct {
+	cnv  sync.Map     // Converters and their Go types (cachedConverter).
+	enc  sync.Map     // Encoders (convert.AnyToAny, may be nil).
+	nCnv atomic.Int32 // Number of cached converters.
+	nEnc atomic.Int32 // Number of cached encoders.
+}
+
+// cachedConverter is the converter with its Go type stored in [lookupCache].
+type cachedConverter struct {
+	cnv convert.AnyToAny
+	rt  reflect.Type
+}
+
+// converter returns the converter and the Go type for the composite type name
+// from the cache. When not cached, it builds them. Only supported type names
+// are cached, so decoding untrusted input doesn't fill the cache with
+// garbage.
+func (c *lookupCache) converter(
+	reg *Registry,
+	typ string,
+) (convert.AnyToAny, reflect.Type) {
+
+	if v, ok := c.cnv.Load(typ); ok {
+		cc := v.(cachedConverter)
+		return cc.cnv, cc.rt
+	}
+	cnv, rt := compositeConverter(reg, typ)
+	if cnv != nil && c.nCnv.Load() < maxCached {
+		cc := cachedConverter{cnv: cnv, rt: rt}
+		if _, loaded := c.cnv.LoadOrStore(typ, cc); !loaded {
+			c.nCnv.Add(1)
+		}
+	}
+	return cnv, rt
+}
+
+// encoder returns the encoder for the composite type name from the cache.
+// When not cached, it builds it.
+func (c *lookupCache) encoder(reg *Registry, typ string) convert.AnyToAny {
+	if v, ok := c.enc.Load(typ); ok {
+		return v.(convert.AnyToAny)
+	}
+	enc := compositeEncoder(reg, typ)
+	if c.nEnc.Load() < maxCached {
+		if _, loaded := c.enc.LoadOrStore(typ, enc); !loaded {
+			c.nEnc.Add(1)
+		}
+	}
+	return enc
+}
@@ -0,0 +1,259 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
+)
+
+func Test_Registry_Freeze(t *testing.T) {
+	t.Run("freeze", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+
+		// --- When ---
+		reg.Freeze()
+
+		// --- Then ---
+		assert.True(t, reg.IsFrozen())
+		assert.NotNil(t, reg.Converter(Int))
+		assert.NotNil(t, reg.Encoder(Time))
+		assert.Equal(t, reflect.TypeFor[[]int](), reg.GoType("[]int"))
+	})
+
+	t.Run("freeze twice", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+		reg.Freeze()
+		snap := reg.snap.Load()
+
+		// --- When ---
+		reg.Freeze()
+
+		// --- Then ---
+		assert.True(t, reg.IsFrozen())
+		assert.Same(t, snap, reg.snap.Load())
+	})
+
+	t.Run("registrations panic", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := DefaultRegistry()
+		reg.Freeze()
+		snap := reg.snap.Load()
+		rt := reflect.TypeFor[int]()
+
+		// --- Then ---
+		assert.PanicContain(t, "registry is frozen", func() {
+			reg.Register(Int, cnv)
+		})
+		assert.PanicContain(t, "registry is frozen", func() {
+			reg.RegisterType("abc", rt, cnv)
+		})
+		assert.PanicContain(t, "registry is frozen", func() {
+			reg.RegisterNumber("abc", rt, cnv)
+		})
+		assert.PanicContain(t, "registry is frozen", func() {
+			reg.RegisterEncoder(Int, cnv)
+		})
+		assert.PanicContain(t, "registry is frozen", func() {
+			reg.Unregister(Int)
+		})
+		assert.PanicContain(t, "registry is frozen", func() {
+			reg.Block(Int)
+		})
+		assert.Same(t, snap, reg.snap.Load())
+	})
+
+	t.Run("registrations panic with strict policy", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry(WithStrictRegister())
+		reg.Freeze()
+
+		// --- Then ---
+		assert.PanicContain(t, "registry is frozen", func() {
+			reg.Register(Int, cnv)
+		})
+	})
+
+	t.Run("strict registrations fail", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+		reg.Freeze()
+
+		// --- When ---
+		err := reg.RegisterStrict(Int, cnv)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrFrozen, err)
+		assert.False(t, reg.Has(Int))
+		assert.PanicContain(t, "registry is frozen", func() {
+			reg.MustRegister(Int, cnv)
+		})
+	})
+
+	t.Run("register struct and interface", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+		reg.Freeze()
+
+		// --- When ---
+		err0 := RegisterStruct[TCircle]("circle", WithRegistry(reg))
+		err1 := RegisterInterface[TShape]("shape", nil, WithRegistry(reg))
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrFrozen, err0)
+		assert.ErrorEqual(t, "RegisterStruct: registry is frozen", err0)
+		assert.ErrorIs(t, ErrFrozen, err1)
+		assert.False(t, reg.Has("circle"))
+		assert.False(t, reg.Has("shape"))
+	})
+
+	t.Run("clone is not frozen", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := DefaultRegistry()
+		reg.Freeze()
+
+		// --- When ---
+		have := reg.Clone()
+
+		// --- Then ---
+		assert.False(t, have.IsFrozen())
+		assert.NoError(t, have.RegisterStrict("abc", cnv))
+		assert.False(t, reg.Has("abc"))
+	})
+
+	t.Run("decoding and encoding", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+		reg.Freeze()
+		val := New(map[string][]time.Duration{"a": {time.Second}})
+
+		// --- When ---
+		data := must.Value(Marshal(reg, val))
+
+		// --- Then ---
+		have := &Value{}
+		assert.NoError(t, Unmarshal(reg, data, have))
+		assert.Equal(t, val.val, have.val)
+	})
+}
+
+func Test_Registry_IsFrozen(t *testing.T) {
+	// --- Given ---
+	reg := NewRegistry()
+
+	// --- Then ---
+	assert.False(t, reg.IsFrozen())
+}
+
+func Test_lookupCache(t *testing.T) {
+	t.Run("composite converters are cached", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+		reg.Freeze()
+
+		// --- When ---
+		have := reg.Converter("[]time.Duration")
+
+		// --- Then ---
+		assert.NotNil(t, have)
+		assert.Same(t, have, reg.Converter("[]time.Duration"))
+		assert.Equal(t, int32(1), reg.snap.Load().cache.nCnv.Load())
+	})
+
+	t.Run("composite encoders are cached", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+		reg.Freeze()
+
+		// --- When ---
+		have := reg.Encoder("[]time.Duration")
+
+		// --- Then ---
+		assert.NotNil(t, have)
+		assert.Same(t, have, reg.Encoder("[]time.Duration"))
+		assert.Nil(t, reg.Encoder("[]int"))
+		_, cached := reg.snap.Load().cache.enc.Load("[]int")
+		assert.True(t, cached)
+	})
+
+	t.Run("unsupported types are not cached", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+		reg.Freeze()
+
+		// --- When ---
+		have := reg.Converter("[]abc")
+
+		// --- Then ---
+		assert.Nil(t, have)
+		assert.Equal(t, int32(0), reg.snap.Load().cache.nCnv.Load())
+	})
+
+	t.Run("cache size is limited", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+		reg.Freeze()
+
+		// --- When ---
+		for i := range maxCached + 10 {
+			reg.Converter(fmt.Sprintf("[%d]int", i))
+		}
+
+		// --- Then ---
+		assert.Equal(t, int32(maxCached), reg.snap.Load().cache.nCnv.Load())
+		assert.NotNil(t, reg.Converter(fmt.Sprintf("[%d]int", maxCached+1)))
+	})
+
+	t.Run("concurrent lookups", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+
+		// --- When ---
+		var wg sync.WaitGroup
+		for i := range 50 {
+			wg.Go(func() {
+				reg.Converter(fmt.Sprintf("[%d]time.Time", i%5))
+				reg.Encoder(fmt.Sprintf("[%d]time.Time", i%5))
+			})
+			if i == 25 {
+				wg.Go(reg.Freeze)
+			}
+		}
+		wg.Wait()
+
+		// --- Then ---
+		assert.True(t, reg.IsFrozen())
+	})
+}
+
+func Benchmark_Registry_Converter_composite(b *testing.B) {
+	b.Run("not frozen", func(b *testing.B) {
+		reg := DefaultRegistry()
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = reg.Converter("[]time.Duration")
+		}
+	})
+
+	b.Run("frozen", func(b *testing.B) {
+		reg := DefaultRegistry()
+		reg.Freeze()
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = reg.Converter("[]time.Duration")
+		}
+	})
+}
@@ -4,34 +4,204 @@
 package jsontype
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 
 	"github.com/ctx42/convert/pkg/convert"
 )
 
+// Marshal marshals the value to its JSON representation using encoders from
+// [Registry]. See [AppendJSON] for the details.
+func Marshal(reg *Registry, val *Value) ([]byte, error) {
+	buf := bufPool.Get().(*[]byte)
+	out, err := AppendJSON((*buf)[:0], reg, val)
+	var data []byte
+	if err == nil {
+		data = bytes.Clone(out)
+	}
+	if cap(out) <= maxPooledBuf {
+		*buf = out[:0]
+		bufPool.Put(buf)
+	}
+	return data, err
+}
+
+// envelope returns the value ready to be marshaled with [json.Marshal].
+func envelope(reg *Registry, val *Value) (any, error) {
+	if val == nil || val.typ == "" {
+		return nil, convert.ErrInvValue
+	}
+	if u, ok := val.val.(*UnknownValue); ok && u.typ == val.typ {
+		return u, nil
+	}
+	v, err := val.encode(reg)
+	if err != nil {
+		return nil, fmt.Errorf("jsontype: %w", err)
+	}
+	return map[string]any{"type": val.typ, "value": v}, nil
+}
+
 // Unmarshal unmarshals JSON representation of the value using [Registry].
-func Unmarshal(reg *Registry, bytes []byte, val *Value) error {
-	tmp := struct {
-		Type  string `json:"type"`
-		Value any    `json:"value"`
-	}{}
-	if err := json.Unmarshal(bytes, &tmp); err != nil {
+//
+// Numbers are passed to converters as float64, except for converters
+// registered with [Registry.RegisterNumber], like the built-in integer
+// converters, which get them as [json.Number].
+//
+// The options other than [WithRegistry] are supported, use
+// [WithAllowedTypes] or [WithTypeFilter] when decoding untrusted input.
+func Unmarshal(reg *Registry, bytes []byte, val *Value, opts ...Option) error {
+	def := newOptions(opts...)
+	def.reg = reg
+	return unmarshal(def, bytes, val)
+}
+
+// UnmarshalValue unmarshals JSON representation of the value. By default, the
+// package-level registry is used, use [WithRegistry] to provide a custom one.
+// Use [WithUnknown] to decode values of unsupported types as [UnknownValue],
+// and [WithAllowedTypes] to limit the accepted types.
+func UnmarshalValue(data []byte, opts ...Option) (*Value, error) {
+	val := &Value{}
+	if err := unmarshal(newOptions(opts...), data, val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// rawValue represents JSON representation of the value before the value is
+// converted.
+type rawValue struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// unmarshal unmarshals JSON representation of the value using options.
+func unmarshal(def *Options, data []byte, val *Value) error {
+	tmp := rawValue{}
+	if err := unmarshalEnvelope(def, data, &tmp); err != nil {
 		return fmt.Errorf("jsontype: %w", err)
 	}
+	if err := def.allowed(tmp.Type); err != nil {
+		return err
+	}
 
-	var err error
-	cnv := reg.Converter(tmp.Type)
+	cnv := def.reg.decoder(tmp.Type)
 	if cnv == nil {
+		if def.unknown && tmp.Type != "" {
+			val.typ = tmp.Type
+			val.val = newUnknownValue(tmp.Type, data, tmp.Value)
+			val.lazy = nil
+			return nil
+		}
 		return fmt.Errorf("%w: %s", convert.ErrUnsType, tmp.Type)
 	}
+	if def.lazy {
+		val.typ = tmp.Type
+		val.val = nil
+		val.lazy = &lazyValue{cnv: cnv, raw: tmp.Value}
+		return nil
+	}
+	v, err := decodeValue(cnv, tmp.Value)
+	if err != nil {
+		return err
+	}
 	val.typ = tmp.Type
-	if val.val, err = cnv(tmp.Value); err != nil {
-		return fmt.Errorf("jsontype: %w", err)
+	val.val = v
+	val.lazy = nil
+	return nil
+}
+
+// decodeValue decodes JSON representation of the value and converts it using
+// the converter.
+func decodeValue(cnv convert.AnyToAny, data []byte) (any, error) {
+	v, err := decodeNumber(data)
+	if err != nil {
+		return nil, fmt.Errorf("jsontype: %w", err)
+	}
+	if v, err = cnv(v); err != nil {
+		return nil, fmt.Errorf("jsontype: %w", err)
+	}
+	return v, nil
+}
+
+// unmarshalEnvelope unmarshals data to the value. In the strict mode, unknown
+// fields are rejected.
+func unmarshalEnvelope(def *Options, data []byte, v any) error {
+	if !def.strict {
+		return json.Unmarshal(data, v)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return errors.New("invalid data after top-level value")
 	}
 	return nil
 }
 
+// decodeNumber decodes JSON representation of the value keeping numbers as
+// [json.Number]. Returns nil for empty data.
+func decodeNumber(data []byte) (any, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// floatNumbers returns a converter which replaces [json.Number] values with
+// float64 before calling cnv.
+func floatNumbers(cnv convert.AnyToAny) convert.AnyToAny {
+	return func(value any) (any, error) {
+		value, _ = numbersToFloat64(value)
+		return cnv(value)
+	}
+}
+
+// numbersToFloat64 returns a copy of the value with all [json.Number] values
+// replaced by float64. It walks slices and maps produced by the JSON decoder.
+// Returns true if at least one number was replaced.
+func numbersToFloat64(v any) (any, bool) {
+	switch val := v.(type) {
+	case json.Number:
+		f64, err := val.Float64()
+		if err != nil {
+			return v, false
+		}
+		return f64, true
+
+	case []any:
+		var found bool
+		ret := make([]any, len(val))
+		for i, elem := range val {
+			var ok bool
+			ret[i], ok = numbersToFloat64(elem)
+			found = found || ok
+		}
+		return ret, found
+
+	case map[string]any:
+		var found bool
+		ret := make(map[string]any, len(val))
+		for key, elem := range val {
+			var ok bool
+			ret[key], ok = numbersToFloat64(elem)
+			found = found || ok
+		}
+		return ret, found
+	}
+	return v, false
+}
+
 // keyValue returns the value represented by the key from the given map. Returns
 // the value and true if it exists. Returns nil and false if it doesn't or when
 // the map is empty or nil.
@@ -4,6 +4,9 @@
 package jsontype
 
 import (
+	"encoding/json"
+	"errors"
+	"reflect"
 	"testing"
 	"time"
 
@@ -11,6 +14,74 @@ import (
 	"github.com/ctx42/testing/pkg/assert"
 )
 
+func Test_Marshal(t *testing.T) {
+	t.Run("without encoder", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+		val := New(uint(42))
+
+		// --- When ---
+		have, err := Marshal(reg, val)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.JSON(t, `{"type":"uint","value":42}`, string(have))
+	})
+
+	t.Run("with encoder", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+		reg.RegisterEncoder(Duration, convert.ToAnyAny(DurationToString))
+		val := New(time.Minute)
+
+		// --- When ---
+		have, err := Marshal(reg, val)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.JSON(t, `{"type":"time.Duration","value":"1m0s"}`, string(have))
+	})
+
+	t.Run("nil value is not encoded", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+		reg.RegisterEncoder(Nil, func(any) (any, error) { return 42, nil })
+		val := &Value{typ: Nil}
+
+		// --- When ---
+		have, err := Marshal(reg, val)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.JSON(t, `{"type":"nil","value":null}`, string(have))
+	})
+
+	t.Run("error - encoder", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+		reg.RegisterEncoder(Duration, convert.ToAnyAny(DurationToString))
+		val := &Value{typ: Duration, val: 42}
+
+		// --- When ---
+		have, err := Marshal(reg, val)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "jsontype: invalid type: expected time.Duration got int"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - nil Value", func(t *testing.T) {
+		// --- When ---
+		have, err := Marshal(NewRegistry(), nil)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvValue, err)
+		assert.Nil(t, have)
+	})
+}
+
 func Test_Unmarshal(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		// --- Given ---
@@ -28,6 +99,59 @@ func Test_Unmarshal(t *testing.T) {
 		assert.Equal(t, uint8(42), val.val)
 	})
 
+	t.Run("float64 converter with custom error", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) {
+			f64, ok := value.(float64)
+			if !ok {
+				return nil, errors.New("want float64")
+			}
+			return time.Duration(f64) * time.Second, nil
+		}
+		reg := NewRegistry()
+		reg.Register("seconds", cnv)
+		data := `{"type": "seconds", "value": 42}`
+		val := &Value{}
+
+		// --- When ---
+		err := Unmarshal(reg, []byte(data), val)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, 42*time.Second, val.val)
+	})
+
+	t.Run("pass-through converter gets float64 numbers", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+		reg.Register("abc", func(value any) (any, error) { return value, nil })
+		data := `{"type": "abc", "value": {"A": [1, 2.5]}}`
+		val := &Value{}
+
+		// --- When ---
+		err := Unmarshal(reg, []byte(data), val)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"A": []any{1.0, 2.5}}, val.val)
+	})
+
+	t.Run("converter accepting numbers", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+		cnv := func(value any) (any, error) { return value, nil }
+		reg.RegisterNumber("abc", nil, cnv)
+		data := `{"type": "abc", "value": [1]}`
+		val := &Value{}
+
+		// --- When ---
+		err := Unmarshal(reg, []byte(data), val)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []any{json.Number("1")}, val.val)
+	})
+
 	t.Run("nil", func(t *testing.T) {
 		// --- Given ---
 		reg := NewRegistry()
@@ -44,6 +168,58 @@ func Test_Unmarshal(t *testing.T) {
 		assert.Nil(t, val.val)
 	})
 
+	t.Run("uint64 above float64 safe range", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+		rt := reflect.TypeFor[uint64]()
+		reg.RegisterNumber(Uint64, rt, intConverter(convert.AnyToUint64))
+		data := `{"type": "uint64", "value": 9007199254740993}`
+		val := &Value{}
+
+		// --- When ---
+		err := Unmarshal(reg, []byte(data), val)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, Uint64, val.typ)
+		assert.Equal(t, uint64(9007199254740993), val.val)
+	})
+
+	t.Run("float64 converter", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value float64) (time.Duration, error) {
+			return time.Duration(value) * time.Second, nil
+		}
+		reg := NewRegistry()
+		reg.Register("seconds", convert.ToAnyAny(cnv))
+		data := `{"type": "seconds", "value": 42}`
+		val := &Value{}
+
+		// --- When ---
+		err := Unmarshal(reg, []byte(data), val)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "seconds", val.typ)
+		assert.Equal(t, 42*time.Second, val.val)
+	})
+
+	t.Run("missing value", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+		reg.Register(Nil, NilConverter)
+		data := `{"type": "nil"}`
+		val := &Value{}
+
+		// --- When ---
+		err := Unmarshal(reg, []byte(data), val)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, Nil, val.typ)
+		assert.Nil(t, val.val)
+	})
+
 	t.Run("error - invalid JSON", func(t *testing.T) {
 		// --- Given ---
 		reg := NewRegistry()
@@ -71,6 +247,49 @@ func Test_Unmarshal(t *testing.T) {
 		assert.ErrorEqual(t, "unsupported type: unknown", err)
 	})
 
+	t.Run("with options", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "money", "value": 1}`
+		val := &Value{}
+
+		// --- When ---
+		err := Unmarshal(registry, []byte(data), val, WithUnknown())
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "money", val.typ)
+	})
+
+	t.Run("allowed type", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint8", "value": 42}`
+		val := &Value{}
+		opt := WithAllowedTypes(Uint8)
+
+		// --- When ---
+		err := Unmarshal(registry, []byte(data), val, opt)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, uint8(42), val.val)
+	})
+
+	t.Run("error - type not allowed", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "[]uint8", "value": [42]}`
+		val := &Value{}
+		opts := []Option{WithAllowedTypes(Uint8), WithUnknown(), WithLazy()}
+
+		// --- When ---
+		err := Unmarshal(registry, []byte(data), val, opts...)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrTypeNotAllowed, err)
+		assert.ErrorEqual(t, "type not allowed: []uint8", err)
+		assert.Empty(t, val.typ)
+		assert.Nil(t, val.lazy)
+	})
+
 	t.Run("error - invalid format", func(t *testing.T) {
 		// --- Given ---
 		reg := NewRegistry()
@@ -89,6 +308,271 @@ func Test_Unmarshal(t *testing.T) {
 	})
 }
 
+func Test_UnmarshalValue(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint64", "value": 18446744073709551615}`
+
+		// --- When ---
+		have, err := UnmarshalValue([]byte(data))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, Uint64, have.typ)
+		assert.Equal(t, uint64(1<<64-1), have.val)
+	})
+
+	t.Run("custom registry", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+		data := `{"type": "circle", "value": {"r": 1}}`
+
+		// --- When ---
+		have, err := UnmarshalValue([]byte(data), WithRegistry(reg))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "circle", have.typ)
+		assert.Equal(t, TCircle{R: 1}, have.val)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "money", "value": {"amount": 1}}`
+
+		// --- When ---
+		have, err := UnmarshalValue([]byte(data), WithUnknown())
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "money", have.typ)
+		u, _ := assert.SameType(t, &UnknownValue{}, have.val)
+		assert.Equal(t, "money", u.typ)
+		assert.Equal(t, data, string(u.raw))
+		assert.Equal(t, `{"amount": 1}`, string(u.value))
+	})
+
+	t.Run("error - unknown type", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "money", "value": {"amount": 1}}`
+
+		// --- When ---
+		have, err := UnmarshalValue([]byte(data))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		assert.ErrorEqual(t, "unsupported type: money", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - type not allowed", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint64", "value": 1}`
+		opt := WithTypeFilter(func(name string) bool { return name != Uint64 })
+
+		// --- When ---
+		have, err := UnmarshalValue([]byte(data), opt)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrTypeNotAllowed, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - missing type with unknown option", func(t *testing.T) {
+		// --- Given ---
+		data := `{"value": 1}`
+
+		// --- When ---
+		have, err := UnmarshalValue([]byte(data), WithUnknown())
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - known type conversion", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint8", "value": 256}`
+
+		// --- When ---
+		have, err := UnmarshalValue([]byte(data), WithUnknown())
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvRange, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_unmarshalEnvelope(t *testing.T) {
+	t.Run("not strict", func(t *testing.T) {
+		// --- Given ---
+		def := &Options{}
+		var have map[string]int
+
+		// --- When ---
+		err := unmarshalEnvelope(def, []byte(`{"a": 1}`), &have)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int{"a": 1}, have)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		// --- Given ---
+		def := &Options{strict: true}
+		var have struct{ A int }
+
+		// --- When ---
+		err := unmarshalEnvelope(def, []byte(`{"A": 1} `), &have)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, 1, have.A)
+	})
+
+	t.Run("error - strict unknown field", func(t *testing.T) {
+		// --- Given ---
+		def := &Options{strict: true}
+		var have struct{ A int }
+
+		// --- When ---
+		err := unmarshalEnvelope(def, []byte(`{"B": 1}`), &have)
+
+		// --- Then ---
+		assert.ErrorEqual(t, "json: unknown field \"B\"", err)
+	})
+
+	t.Run("error - strict data after value", func(t *testing.T) {
+		// --- Given ---
+		def := &Options{strict: true}
+		var have struct{ A int }
+
+		// --- When ---
+		err := unmarshalEnvelope(def, []byte(`{"A": 1} {}`), &have)
+
+		// --- Then ---
+		assert.ErrorEqual(t, "invalid data after top-level value", err)
+	})
+}
+
+func Test_decodeNumber(t *testing.T) {
+	t.Run("number", func(t *testing.T) {
+		// --- When ---
+		have, err := decodeNumber([]byte("18446744073709551615"))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, json.Number("18446744073709551615"), have)
+	})
+
+	t.Run("nested numbers", func(t *testing.T) {
+		// --- When ---
+		have, err := decodeNumber([]byte(`{"A": [1, 2]}`))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := map[string]any{"A": []any{json.Number("1"), json.Number("2")}}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		// --- When ---
+		have, err := decodeNumber(nil)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - invalid JSON", func(t *testing.T) {
+		// --- When ---
+		have, err := decodeNumber([]byte("{!!!}"))
+
+		// --- Then ---
+		assert.ErrorContain(t, "invalid character", err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_floatNumbers(t *testing.T) {
+	t.Run("number", func(t *testing.T) {
+		// --- Given ---
+		cnv := floatNumbers(convert.ToAnyAny(convert.Float64ToUint8))
+
+		// --- When ---
+		have, err := cnv(json.Number("42"))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, uint8(42), have)
+	})
+
+	t.Run("nested numbers", func(t *testing.T) {
+		// --- Given ---
+		cnv := floatNumbers(func(value any) (any, error) { return value, nil })
+
+		// --- When ---
+		have, err := cnv(map[string]any{"A": []any{json.Number("1")}})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"A": []any{1.0}}, have)
+	})
+
+	t.Run("error - from the converter", func(t *testing.T) {
+		// --- Given ---
+		cnv := floatNumbers(convert.ToAnyAny(convert.Float64ToUint8))
+
+		// --- When ---
+		_, err := cnv(json.Number("1000"))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvRange, err)
+	})
+}
+
+func Test_numbersToFloat64(t *testing.T) {
+	t.Run("number", func(t *testing.T) {
+		// --- When ---
+		have, ok := numbersToFloat64(json.Number("4.2"))
+
+		// --- Then ---
+		assert.True(t, ok)
+		assert.Equal(t, 4.2, have)
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		// --- Given ---
+		v := map[string]any{"A": []any{json.Number("1"), "abc"}}
+
+		// --- When ---
+		have, ok := numbersToFloat64(v)
+
+		// --- Then ---
+		assert.True(t, ok)
+		assert.Equal(t, map[string]any{"A": []any{1.0, "abc"}}, have)
+		assert.Equal(t, json.Number("1"), v["A"].([]any)[0])
+	})
+
+	t.Run("no numbers", func(t *testing.T) {
+		// --- When ---
+		have, ok := numbersToFloat64([]any{"abc"})
+
+		// --- Then ---
+		assert.False(t, ok)
+		assert.Equal(t, []any{"abc"}, have)
+	})
+
+	t.Run("invalid number", func(t *testing.T) {
+		// --- When ---
+		have, ok := numbersToFloat64(json.Number("abc"))
+
+		// --- Then ---
+		assert.False(t, ok)
+		assert.Equal(t, json.Number("abc"), have)
+	})
+}
+
 func Test_keyValue(t *testing.T) {
 	t.Run("key exists", func(t *testing.T) {
 		// --- Given ---
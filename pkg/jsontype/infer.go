@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/ctx42/convert/pkg/convert"
+)
+
+// inferRules are the default rules used by [Infer].
+var inferRules = []InferRule{InferInteger, InferTime}
+
+// InferRule infers the Go value for a JSON value decoded as [json.Number],
+// string or bool. Returns false when the rule does not apply to the value.
+type InferRule func(v any) (any, bool)
+
+// DefaultInferRules returns the rules used by [Infer] when no rules are set
+// with [WithInferRules].
+func DefaultInferRules() []InferRule {
+	return slices.Clone(inferRules)
+}
+
+// InferInteger infers int64 for integral numbers which fit in it and uint64
+// for integral numbers which only fit in uint64.
+func InferInteger(v any) (any, bool) {
+	num, ok := v.(json.Number)
+	if !ok {
+		return nil, false
+	}
+	ret, err := parseInteger(num)
+	if err != nil {
+		return nil, false
+	}
+	if f64, ok := ret.(float64); ok {
+		if f64 != math.Trunc(f64) {
+			return nil, false
+		}
+		if f64 < math.MinInt64 || f64 >= math.MaxUint64 {
+			return nil, false
+		}
+		if f64 >= math.MaxInt64 {
+			return uint64(f64), true
+		}
+		return int64(f64), true
+	}
+	return ret, true
+}
+
+// InferTime infers [time.Time] for strings in the [time.RFC3339Nano] format.
+func InferTime(v any) (any, bool) {
+	str, ok := v.(string)
+	if !ok {
+		return nil, false
+	}
+	tim, err := time.Parse(time.RFC3339Nano, str)
+	if err != nil {
+		return nil, false
+	}
+	return tim, true
+}
+
+// Infer decodes plain JSON and infers Go types of its values. Maps and slices
+// are decoded as map[string]any and []any, all other values are wrapped in
+// [Value] with the type name of the inferred Go type, so the result may be
+// encoded with explicit types as [Map] or [List].
+//
+// Rules are tried in order, the first rule which applies wins. Values no rule
+// applies to are decoded as float64, string, bool or nil. By default, the
+// [DefaultInferRules] are used, use [WithInferRules] to set custom rules. The
+// inferred Go types must be registered in the registry.
+func Infer(data []byte, opts ...Option) (any, error) {
+	def := newOptions(opts...)
+	v, err := decodeNumber(data)
+	if err != nil {
+		return nil, fmt.Errorf("jsontype: %w", err)
+	}
+	if v, err = inferTree(def, v); err != nil {
+		return nil, fmt.Errorf("jsontype: %w", err)
+	}
+	return v, nil
+}
+
+// inferTree walks the decoded JSON and wraps values in [Value] instances.
+func inferTree(def *Options, v any) (any, error) {
+	switch val := v.(type) {
+	case map[string]any:
+		for key, elem := range val {
+			ev, err := inferTree(def, elem)
+			if err != nil {
+				return nil, fmt.Errorf("key %q: %w", key, err)
+			}
+			val[key] = ev
+		}
+		return val, nil
+
+	case []any:
+		for i, elem := range val {
+			var err error
+			if val[i], err = inferTree(def, elem); err != nil {
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+		return val, nil
+
+	default:
+		return inferValue(def, val)
+	}
+}
+
+// inferValue infers the Go type of the JSON value.
+func inferValue(def *Options, v any) (*Value, error) {
+	if v == nil {
+		return &Value{typ: Nil, val: nil}, nil
+	}
+	ret, ok := v, false
+	for _, rule := range def.rules {
+		if ret, ok = rule(v); ok {
+			break
+		}
+	}
+	if !ok {
+		ret = v
+		if num, isNum := v.(json.Number); isNum {
+			var err error
+			if ret, err = parseFloat(num); err != nil {
+				return nil, err
+			}
+		}
+	}
+	rt := reflect.TypeOf(ret)
+	typ := def.reg.typeName(rt)
+	if typ == "" {
+		return nil, fmt.Errorf("%w: %s", convert.ErrUnsType, rt)
+	}
+	return &Value{typ: typ, val: ret}, nil
+}
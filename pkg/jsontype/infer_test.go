@@ -0,0 +1,198 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ctx42/convert/pkg/convert"
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
+)
+
+func Test_DefaultInferRules(t *testing.T) {
+	// --- When ---
+	have := DefaultInferRules()
+
+	// --- Then ---
+	assert.Len(t, 2, have)
+	assert.NotSame(t, &inferRules[0], &have[0])
+}
+
+func Test_InferInteger_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		v    any
+		want any
+		ok   bool
+	}{
+		{"zero", json.Number("0"), int64(0), true},
+		{"min i64", json.Number("-9223372036854775808"), int64(-1 << 63), true},
+		{"max i64", json.Number("9223372036854775807"), int64(1<<63 - 1), true},
+		{"max i64+1", json.Number("9223372036854775808"), uint64(1 << 63), true},
+		{"max u64", json.Number("18446744073709551615"), uint64(1<<64 - 1), true},
+		{"integral float", json.Number("1.0"), int64(1), true},
+		{"exponent", json.Number("1e3"), int64(1000), true},
+		{"exponent u64", json.Number("1e19"), uint64(1e19), true},
+		{"exponent negative", json.Number("-9e18"), int64(-9e18), true},
+		{"exponent too big", json.Number("2e19"), nil, false},
+		{"fraction", json.Number("1.5"), nil, false},
+		{"max u64+1", json.Number("18446744073709551616"), nil, false},
+		{"invalid", json.Number("abc"), nil, false},
+		{"float64", 1.0, nil, false},
+		{"string", "1", nil, false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- When ---
+			have, ok := InferInteger(tc.v)
+
+			// --- Then ---
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
+
+func Test_InferTime_tabular(t *testing.T) {
+	tim := time.Date(2000, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	tt := []struct {
+		testN string
+
+		v    any
+		want any
+		ok   bool
+	}{
+		{"RFC3339", "2000-01-02T03:04:05Z", tim.Truncate(time.Second), true},
+		{"RFC3339Nano", "2000-01-02T03:04:05.000000006Z", tim, true},
+		{"date", "2000-01-02", nil, false},
+		{"empty", "", nil, false},
+		{"number", json.Number("1"), nil, false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- When ---
+			have, ok := InferTime(tc.v)
+
+			// --- Then ---
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
+
+func Test_Infer(t *testing.T) {
+	t.Run("default rules", func(t *testing.T) {
+		// --- Given ---
+		data := `{
+			"i64": -1,
+			"u64": 18446744073709551615,
+			"f64": 1.5,
+			"time": "2000-01-02T03:04:05Z",
+			"list": ["abc", true, null]
+		}`
+
+		// --- When ---
+		have, err := Infer([]byte(data))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		tim := time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
+		want := map[string]any{
+			"i64":  &Value{typ: "int64", val: int64(-1)},
+			"u64":  &Value{typ: "uint64", val: uint64(1<<64 - 1)},
+			"f64":  &Value{typ: "float64", val: 1.5},
+			"time": &Value{typ: "time.Time", val: tim},
+			"list": []any{
+				&Value{typ: "string", val: "abc"},
+				&Value{typ: "bool", val: true},
+				&Value{typ: "nil", val: nil},
+			},
+		}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("custom rules", func(t *testing.T) {
+		// --- Given ---
+		rule := func(v any) (any, bool) {
+			if v == "one" {
+				return uint8(1), true
+			}
+			return nil, false
+		}
+
+		// --- When ---
+		have, err := Infer([]byte(`["one", 1]`), WithInferRules(rule))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := []any{
+			&Value{typ: "uint8", val: uint8(1)},
+			&Value{typ: "float64", val: 1.0},
+		}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("no rules", func(t *testing.T) {
+		// --- Given ---
+		data := `[1, "2000-01-02T03:04:05Z"]`
+
+		// --- When ---
+		have, err := Infer([]byte(data), WithInferRules())
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := []any{
+			&Value{typ: "float64", val: 1.0},
+			&Value{typ: "string", val: "2000-01-02T03:04:05Z"},
+		}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("re-encode with explicit types", func(t *testing.T) {
+		// --- Given ---
+		data := `{"id": 18446744073709551615, "at": "2000-01-02T03:04:05Z"}`
+		v := must.Value(Infer([]byte(data)))
+
+		// --- When ---
+		have, err := json.Marshal(Map(v.(map[string]any)))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := `{
+			"at": {"type": "time.Time", "value": "2000-01-02T03:04:05Z"},
+			"id": {"type": "uint64", "value": 18446744073709551615}
+		}`
+		assert.JSON(t, want, string(have))
+	})
+
+	t.Run("error - type not registered", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+
+		// --- When ---
+		have, err := Infer([]byte(`{"a": [1]}`), WithRegistry(reg))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		wMsg := "jsontype: key \"a\": index 0: unsupported type: int64"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - invalid JSON", func(t *testing.T) {
+		// --- When ---
+		have, err := Infer([]byte(`{!}`))
+
+		// --- Then ---
+		assert.ErrorContain(t, "jsontype: invalid character", err)
+		assert.Nil(t, have)
+	})
+}
@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/ctx42/convert/pkg/convert"
+)
+
+// RegisterInterface registers a converter, an encoder and the Go type for
+// the interface type I under the given type name. The impls is a list of type
+// names of concrete types implementing the interface.
+//
+// Interface values are encoded in the same format as [Value], so the concrete
+// type name is preserved. When decoded, only type names from the impls list
+// are accepted, and the decoded value must implement the interface. The type
+// name can be used as an element of composite types (like "[]shape") and the
+// interface can be used as a type of fields of structs registered with
+// [RegisterStruct].
+//
+// By default, the package-level registry is used, use [WithRegistry] to
+// provide a custom one. Returns an error when I is not an interface or when
+// any of the implementation type names is registered with the Go type which
+// doesn't implement I. For registries created with [WithStrictRegister],
+// returns [ConflictError] when the type name is already registered.
+func RegisterInterface[I any](
+	name string,
+	impls []string,
+	opts ...Option,
+) error {
+
+	rt := reflect.TypeFor[I]()
+	if rt.Kind() != reflect.Interface {
+		format := "RegisterInterface: %w: expected interface got %s"
+		return fmt.Errorf(format, convert.ErrInvType, rt)
+	}
+	def := newOptions(opts...)
+	for _, impl := range impls {
+		if err := implements(def.reg, impl, rt); err != nil {
+			return fmt.Errorf("RegisterInterface: %w", err)
+		}
+	}
+	impls = slices.Clone(impls)
+	cnv := interfaceConverter(def.reg, rt, impls)
+	_, err := def.reg.register(name, rt, cnv, true, false)
+	if err != nil {
+		return fmt.Errorf("RegisterInterface: %w", err)
+	}
+	def.reg.RegisterEncoder(name, interfaceEncoder(def.reg, rt, impls))
+	return nil
+}
+
+// UnmarshalSlice unmarshals a JSON array of values in the format returned by
+// [Value.MarshalJSON] into a slice of I. The type I must be registered, for
+// interfaces use [RegisterInterface]. By default, the package-level registry
+// is used, use [WithRegistry] to provide a custom one.
+//
+// Use [WithAllowedTypes] or [WithTypeFilter] to limit the accepted types. The
+// filter is applied to the type name of I and, for interfaces, to the type
+// names of the array elements.
+func UnmarshalSlice[I any](data []byte, opts ...Option) ([]I, error) {
+	def := newOptions(opts...)
+	rt := reflect.TypeFor[I]()
+	name := def.reg.typeName(rt)
+	if name == "" {
+		return nil, fmt.Errorf("%w: %s", convert.ErrUnsType, rt)
+	}
+	if err := def.allowed(name); err != nil {
+		return nil, err
+	}
+	src, err := decodeNumber(data)
+	if err != nil {
+		return nil, fmt.Errorf("jsontype: %w", err)
+	}
+	if rt.Kind() == reflect.Interface {
+		if err = allowedElems(def, src); err != nil {
+			return nil, fmt.Errorf("jsontype: %w", err)
+		}
+	}
+	cnv := def.reg.decoder("[]" + name)
+	if cnv == nil {
+		return nil, fmt.Errorf("%w: []%s", convert.ErrUnsType, name)
+	}
+	ret, err := cnv(src)
+	if err != nil {
+		return nil, fmt.Errorf("jsontype: %w", err)
+	}
+	return ret.([]I), nil // nolint: forcetypeassert
+}
+
+// allowedElems returns an error when the type name of an array element in the
+// [Value.Map] format is rejected by the type filter. Other values are checked
+// by the converter.
+func allowedElems(def *Options, src any) error {
+	elems, _ := src.([]any)
+	for i, elem := range elems {
+		m, _ := elem.(map[string]any)
+		typ, ok := m["type"].(string)
+		if !ok {
+			continue
+		}
+		if err := def.allowed(typ); err != nil {
+			return fmt.Errorf("index %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// implements returns an error when the type name is registered with the Go
+// type which doesn't implement the interface. Type names with unknown Go type
+// are accepted; they are checked when decoded.
+func implements(reg *Registry, name string, iface reflect.Type) error {
+	rt := reg.GoType(name)
+	if rt == nil || rt.Implements(iface) {
+		return nil
+	}
+	format := "%w: %s (%s) does not implement %s"
+	return fmt.Errorf(format, convert.ErrInvType, name, rt, iface)
+}
+
+// interfaceConverter returns a converter for the interface type. It expects
+// a JSON object in the format returned by [Value.MarshalJSON] or null.
+func interfaceConverter(
+	reg *Registry,
+	rt reflect.Type,
+	impls []string,
+) convert.AnyToAny {
+
+	return func(value any) (any, error) {
+		if value == nil {
+			return nil, nil
+		}
+		src, ok := value.(map[string]any)
+		if !ok {
+			if reflect.TypeOf(value).Implements(rt) {
+				return value, nil
+			}
+			return nil, invTypeError(rt, value)
+		}
+
+		typ, ok := src["type"].(string)
+		if !ok {
+			return nil, fmt.Errorf("type field: %w", convert.ErrInvFormat)
+		}
+		if !slices.Contains(impls, typ) {
+			format := "%w: %s is not an implementation of %s"
+			return nil, fmt.Errorf(format, convert.ErrUnsType, typ, rt)
+		}
+		cnv := reg.decoder(typ)
+		if cnv == nil {
+			return nil, fmt.Errorf("%w: %s", convert.ErrUnsType, typ)
+		}
+		ret, err := cnv(src["value"])
+		if err != nil {
+			return nil, err
+		}
+		if ret == nil {
+			return nil, nil
+		}
+		if !reflect.TypeOf(ret).Implements(rt) {
+			format := "%w: %s (%T) does not implement %s"
+			return nil, fmt.Errorf(format, convert.ErrInvType, typ, ret, rt)
+		}
+		return ret, nil
+	}
+}
+
+// interfaceEncoder returns an encoder for the interface type. It encodes the
+// value in the [Value.Map] format using the first implementation type name
+// registered for the value's Go type.
+func interfaceEncoder(
+	reg *Registry,
+	rt reflect.Type,
+	impls []string,
+) convert.AnyToAny {
+
+	return func(value any) (any, error) {
+		vt := reflect.TypeOf(value)
+		idx := slices.IndexFunc(impls, func(impl string) bool {
+			return reg.GoType(impl) == vt
+		})
+		if idx < 0 {
+			format := "%w: %s is not a registered implementation of %s"
+			return nil, fmt.Errorf(format, convert.ErrUnsType, vt, rt)
+		}
+		val := &Value{typ: impls[idx], val: value}
+		v, err := val.encode(reg)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": val.typ, "value": v}, nil
+	}
+}
@@ -0,0 +1,454 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/ctx42/convert/pkg/convert"
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
+)
+
+// TShape is an interface used in tests.
+type TShape interface{ Area() float64 }
+
+// TCircle is a [TShape] implementation used in tests.
+type TCircle struct {
+	R float64 `json:"r"`
+}
+
+func (c TCircle) Area() float64 { return 3 * c.R * c.R }
+
+// TSquare is a [TShape] implementation used in tests.
+type TSquare struct {
+	A uint64 `json:"a"`
+}
+
+func (s TSquare) Area() float64 { return float64(s.A * s.A) }
+
+// TDrawing is a struct with interface fields used in tests.
+type TDrawing struct {
+	Main   TShape   `json:"main"`
+	Shapes []TShape `json:"shapes"`
+}
+
+// shapeRegistry returns the default registry with test shapes registered.
+func shapeRegistry(t testing.TB) *Registry {
+	t.Helper()
+	reg := DefaultRegistry()
+	must.Nil(RegisterStruct[TCircle]("circle", WithRegistry(reg)))
+	must.Nil(RegisterStruct[TSquare]("square", WithRegistry(reg)))
+	impls := []string{"circle", "square", "later", "inner"}
+	must.Nil(RegisterInterface[TShape]("shape", impls, WithRegistry(reg)))
+	// Registered after the interface to pass the registration checks.
+	must.Nil(RegisterStruct[TInner]("inner", WithRegistry(reg)))
+	must.Nil(RegisterStruct[TDrawing]("drawing", WithRegistry(reg)))
+	return reg
+}
+
+func Test_RegisterInterface(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+		must.Nil(RegisterStruct[TCircle]("circle", WithRegistry(reg)))
+		impls := []string{"circle", "not-registered"}
+
+		// --- When ---
+		err := RegisterInterface[TShape]("shape", impls, WithRegistry(reg))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.NotNil(t, reg.Converter("shape"))
+		assert.NotNil(t, reg.Encoder("shape"))
+		assert.Equal(t, reflect.TypeFor[TShape](), reg.GoType("shape"))
+		assert.Equal(t, reflect.TypeFor[[]TShape](), reg.GoType("[]shape"))
+	})
+
+	t.Run("error - not an interface", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+
+		// --- When ---
+		err := RegisterInterface[TCircle]("shape", nil, WithRegistry(reg))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "RegisterInterface: invalid type: " +
+			"expected interface got jsontype.TCircle"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, reg.Converter("shape"))
+	})
+
+	t.Run("error - strict register conflict", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry(WithStrictRegister())
+		must.Nil(RegisterStruct[TCircle]("circle", WithRegistry(reg)))
+
+		// --- When ---
+		err := RegisterInterface[TShape]("circle", nil, WithRegistry(reg))
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrConflict, err)
+		wMsg := "RegisterInterface: type name already registered: circle"
+		assert.ErrorContain(t, wMsg, err)
+		assert.Equal(t, reflect.TypeFor[TCircle](), reg.GoType("circle"))
+	})
+
+	t.Run("error - does not implement", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+		impls := []string{"uint"}
+
+		// --- When ---
+		err := RegisterInterface[TShape]("shape", impls, WithRegistry(reg))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "RegisterInterface: invalid type: " +
+			"uint (uint) does not implement jsontype.TShape"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, reg.Converter("shape"))
+	})
+}
+
+func Test_UnmarshalSlice(t *testing.T) {
+	t.Run("interface", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+		data := `[
+			{"type": "circle", "value": {"r": 1.5}},
+			{"type": "square", "value": {"a": 18446744073709551615}},
+			null
+		]`
+
+		// --- When ---
+		have, err := UnmarshalSlice[TShape]([]byte(data), WithRegistry(reg))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := []TShape{TCircle{R: 1.5}, TSquare{A: 1<<64 - 1}, nil}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("concrete type", func(t *testing.T) {
+		// --- Given ---
+		data := `[1, 2]`
+
+		// --- When ---
+		have, err := UnmarshalSlice[uint]([]byte(data))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []uint{1, 2}, have)
+	})
+
+	t.Run("error - not registered", func(t *testing.T) {
+		// --- When ---
+		have, err := UnmarshalSlice[TShape]([]byte(`[]`))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		assert.ErrorEqual(t, "unsupported type: jsontype.TShape", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - slice not supported", func(t *testing.T) {
+		// --- When ---
+		have, err := UnmarshalSlice[[5000]int]([]byte(`[]`))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		assert.ErrorEqual(t, "unsupported type: [5000]int", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - invalid JSON", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+
+		// --- When ---
+		have, err := UnmarshalSlice[TShape]([]byte(`[!]`), WithRegistry(reg))
+
+		// --- Then ---
+		assert.ErrorContain(t, "jsontype: invalid character", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - type not allowed", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+		opts := []Option{WithRegistry(reg), WithAllowedTypes("circle")}
+
+		// --- When ---
+		have, err := UnmarshalSlice[TShape]([]byte(`[]`), opts...)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrTypeNotAllowed, err)
+		assert.ErrorEqual(t, "type not allowed: shape", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - element type not allowed", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+		data := `[
+			{"type": "circle", "value": {"r": 1.5}},
+			{"type": "square", "value": {"a": 1}}
+		]`
+		opts := []Option{WithRegistry(reg), WithAllowedTypes("shape", "circle")}
+
+		// --- When ---
+		have, err := UnmarshalSlice[TShape]([]byte(data), opts...)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrTypeNotAllowed, err)
+		wMsg := "jsontype: index 1: type not allowed: square"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - element", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+		data := `[{"type": "uint", "value": 1}]`
+
+		// --- When ---
+		have, err := UnmarshalSlice[TShape]([]byte(data), WithRegistry(reg))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		wMsg := "jsontype: index 0: unsupported type: " +
+			"uint is not an implementation of jsontype.TShape"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_implements(t *testing.T) {
+	t.Run("implements", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+
+		// --- When ---
+		err := implements(reg, "circle", reflect.TypeFor[TShape]())
+
+		// --- Then ---
+		assert.NoError(t, err)
+	})
+
+	t.Run("not registered", func(t *testing.T) {
+		// --- When ---
+		err := implements(NewRegistry(), "circle", reflect.TypeFor[TShape]())
+
+		// --- Then ---
+		assert.NoError(t, err)
+	})
+
+	t.Run("error - does not implement", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+
+		// --- When ---
+		err := implements(reg, "inner", reflect.TypeFor[TShape]())
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "invalid type: inner (jsontype.TInner) " +
+			"does not implement jsontype.TShape"
+		assert.ErrorEqual(t, wMsg, err)
+	})
+}
+
+func Test_interfaceConverter(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		cnv := shapeRegistry(t).Converter("shape")
+		src := map[string]any{
+			"type":  "circle",
+			"value": map[string]any{"r": json.Number("2")},
+		}
+
+		// --- When ---
+		have, err := cnv(src)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, TCircle{R: 2}, have)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		// --- Given ---
+		cnv := shapeRegistry(t).Converter("shape")
+
+		// --- When ---
+		have, err := cnv(nil)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("already implements", func(t *testing.T) {
+		// --- Given ---
+		cnv := shapeRegistry(t).Converter("shape")
+
+		// --- When ---
+		have, err := cnv(TSquare{A: 1})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, TSquare{A: 1}, have)
+	})
+
+	t.Run("error - invalid type", func(t *testing.T) {
+		// --- Given ---
+		cnv := shapeRegistry(t).Converter("shape")
+
+		// --- When ---
+		have, err := cnv(42)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "invalid type: expected jsontype.TShape got int"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - missing type", func(t *testing.T) {
+		// --- Given ---
+		cnv := shapeRegistry(t).Converter("shape")
+
+		// --- When ---
+		have, err := cnv(map[string]any{"value": 42})
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvFormat, err)
+		assert.ErrorEqual(t, "type field: invalid format", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - implementation not registered", func(t *testing.T) {
+		// --- Given ---
+		cnv := shapeRegistry(t).Converter("shape")
+
+		// --- When ---
+		have, err := cnv(map[string]any{"type": "later", "value": 42})
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		assert.ErrorEqual(t, "unsupported type: later", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - does not implement", func(t *testing.T) {
+		// --- Given ---
+		cnv := shapeRegistry(t).Converter("shape")
+		src := map[string]any{"type": "inner", "value": map[string]any{}}
+
+		// --- When ---
+		have, err := cnv(src)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "invalid type: inner (jsontype.TInner) " +
+			"does not implement jsontype.TShape"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - value", func(t *testing.T) {
+		// --- Given ---
+		cnv := shapeRegistry(t).Converter("shape")
+		src := map[string]any{
+			"type":  "square",
+			"value": map[string]any{"a": json.Number("-1")},
+		}
+
+		// --- When ---
+		have, err := cnv(src)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvRange, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_interfaceEncoder(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		enc := shapeRegistry(t).Encoder("shape")
+
+		// --- When ---
+		have, err := enc(TCircle{R: 2})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := map[string]any{
+			"type":  "circle",
+			"value": map[string]any{"r": 2.0},
+		}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("error - not registered implementation", func(t *testing.T) {
+		// --- Given ---
+		enc := shapeRegistry(t).Encoder("shape")
+
+		// --- When ---
+		have, err := enc(TEmbedded{})
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		wMsg := "unsupported type: jsontype.TEmbedded is not " +
+			"a registered implementation of jsontype.TShape"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_Value_round_trip_interface(t *testing.T) {
+	t.Run("slice", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+		val := &Value{
+			typ: "[]shape",
+			val: []TShape{TCircle{R: 1}, nil, TSquare{A: 1<<64 - 1}},
+		}
+		data := must.Value(Marshal(reg, val))
+		have := &Value{}
+
+		// --- When ---
+		err := Unmarshal(reg, data, have)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, val.typ, have.typ)
+		assert.Equal(t, val.val, have.val)
+	})
+
+	t.Run("struct fields", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+		val := &Value{
+			typ: "drawing",
+			val: TDrawing{
+				Main:   TSquare{A: 2},
+				Shapes: []TShape{TCircle{R: 1}},
+			},
+		}
+		data := must.Value(Marshal(reg, val))
+		have := &Value{}
+
+		// --- When ---
+		err := Unmarshal(reg, data, have)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, val.typ, have.typ)
+		assert.Equal(t, val.val, have.val)
+	})
+}
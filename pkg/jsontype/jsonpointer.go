@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ctx42/convert/pkg/convert"
+)
+
+// escapeToken escapes the JSON Pointer reference token as defined in RFC 6901.
+func escapeToken(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	return strings.ReplaceAll(token, "/", "~1")
+}
+
+// splitPointer splits the JSON Pointer (RFC 6901) to unescaped reference
+// tokens. Returns nil for the empty pointer referring to the whole document.
+func splitPointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		format := "%w: JSON Pointer must start with a slash"
+		return nil, fmt.Errorf(format, convert.ErrInvFormat)
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] != '~' {
+				continue
+			}
+			if j+1 == len(token) || (token[j+1] != '0' && token[j+1] != '1') {
+				format := "%w: invalid JSON Pointer escape sequence"
+				return nil, fmt.Errorf(format, convert.ErrInvFormat)
+			}
+		}
+		token = strings.ReplaceAll(token, "~1", "/")
+		tokens[i] = strings.ReplaceAll(token, "~0", "~")
+	}
+	return tokens, nil
+}
@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"testing"
+
+	"github.com/ctx42/convert/pkg/convert"
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+func Test_escapeToken_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		token string
+		want  string
+	}{
+		{"empty", "", ""},
+		{"plain", "abc", "abc"},
+		{"tilde", "a~b", "a~0b"},
+		{"slash", "a/b", "a~1b"},
+		{"both", "~/", "~0~1"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- When ---
+			have := escapeToken(tc.token)
+
+			// --- Then ---
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
+
+func Test_splitPointer_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		ptr  string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"root key", "/", []string{""}},
+		{"keys", "/a/0/b", []string{"a", "0", "b"}},
+		{"empty keys", "/a//b/", []string{"a", "", "b", ""}},
+		{"escapes", "/a~1b/~0~1/~01", []string{"a/b", "~/", "~1"}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- When ---
+			have, err := splitPointer(tc.ptr)
+
+			// --- Then ---
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
+
+func Test_splitPointer(t *testing.T) {
+	t.Run("error - no leading slash", func(t *testing.T) {
+		// --- When ---
+		have, err := splitPointer("a/b")
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvFormat, err)
+		wMsg := "invalid format: JSON Pointer must start with a slash"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - invalid escape", func(t *testing.T) {
+		// --- When ---
+		have, err := splitPointer("/a~2")
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvFormat, err)
+		wMsg := "invalid format: invalid JSON Pointer escape sequence"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - escape at the end", func(t *testing.T) {
+		// --- When ---
+		have, err := splitPointer("/a~/b")
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvFormat, err)
+		assert.Nil(t, have)
+	})
+}
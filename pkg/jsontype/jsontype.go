@@ -5,8 +5,8 @@
 package jsontype
 
 import (
-	"encoding/json"
 	"fmt"
+	"maps"
 	"reflect"
 	"time"
 
@@ -24,6 +24,65 @@ func Register(typ string, cnv convert.AnyToAny) convert.AnyToAny {
 	return registry.Register(typ, cnv)
 }
 
+// RegisterStrict registers converter for a given type name. Returns
+// [ConflictError] when the type name is already registered. See
+// [Registry.RegisterStrict].
+func RegisterStrict(typ string, cnv convert.AnyToAny) error {
+	return registry.RegisterStrict(typ, cnv)
+}
+
+// MustRegister works like [RegisterStrict] but panics on conflict.
+func MustRegister(typ string, cnv convert.AnyToAny) {
+	registry.MustRegister(typ, cnv)
+}
+
+// RegisterType registers converter and the Go type it returns for a given
+// type name. See [Registry.RegisterType].
+func RegisterType(
+	typ string,
+	rt reflect.Type,
+	cnv convert.AnyToAny,
+) convert.AnyToAny {
+
+	if cnv == nil {
+		return nil
+	}
+	return registry.RegisterType(typ, rt, cnv)
+}
+
+// RegisterNumber registers converter accepting [json.Number] values and the
+// Go type it returns for a given type name. See [Registry.RegisterNumber].
+func RegisterNumber(
+	typ string,
+	rt reflect.Type,
+	cnv convert.AnyToAny,
+) convert.AnyToAny {
+
+	return registry.RegisterNumber(typ, rt, cnv)
+}
+
+// RegisterEncoder registers encoder for a given type name. See
+// [Registry.RegisterEncoder].
+func RegisterEncoder(typ string, enc convert.AnyToAny) convert.AnyToAny {
+	if enc == nil {
+		return nil
+	}
+	return registry.RegisterEncoder(typ, enc)
+}
+
+// SetStrictRegister turns on the strict registration policy of the
+// package-level registry. Registrations done by init functions of other
+// packages may run before it, so their conflicts are returned. Call it from an
+// init function of the main package and treat the returned error as fatal.
+// See [Registry.SetStrictRegister].
+func SetStrictRegister() error { return registry.SetStrictRegister() }
+
+// Freeze freezes the package-level registry. See [Registry.Freeze].
+func Freeze() { registry.Freeze() }
+
+// IsFrozen returns true if the package-level registry is frozen.
+func IsFrozen() bool { return registry.IsFrozen() }
+
 func init() { registry = DefaultRegistry() }
 
 // List of type names supported by the package out of the box.
@@ -52,62 +111,99 @@ const (
 	Nil      = "nil"
 )
 
-// DefaultRegistry returns default registry configuration.
-func DefaultRegistry() *Registry {
-	reg := NewRegistry()
+// DefaultRegistry returns default registry configuration. The options are
+// passed to [NewRegistry].
+func DefaultRegistry(opts ...Option) *Registry {
+	reg := NewRegistry(opts...)
 
-	reg.Register(Byte, convert.ToAnyAny(convert.Float64ToByte))
-	reg.Register(Uint8, convert.ToAnyAny(convert.Float64ToUint8))
-	reg.Register(Uint16, convert.ToAnyAny(convert.Float64ToUint16))
-	reg.Register(Uint32, convert.ToAnyAny(convert.Float64ToUint32))
-	reg.Register(Uint64, convert.ToAnyAny(convert.Float64ToUint64))
-	reg.Register(Uint, convert.ToAnyAny(convert.Float64ToUint))
+	registerNumber[byte](reg, Byte, intConverter(convert.AnyToByte))
+	registerNumber[uint8](reg, Uint8, intConverter(convert.AnyToUint8))
+	registerNumber[uint16](reg, Uint16, intConverter(convert.AnyToUint16))
+	registerNumber[uint32](reg, Uint32, intConverter(convert.AnyToUint32))
+	registerNumber[uint64](reg, Uint64, intConverter(convert.AnyToUint64))
+	registerNumber[uint](reg, Uint, intConverter(convert.AnyToUint))
 
-	reg.Register(Int8, convert.ToAnyAny(convert.Float64ToInt8))
-	reg.Register(Int16, convert.ToAnyAny(convert.Float64ToInt16))
-	reg.Register(Rune, convert.ToAnyAny(convert.Float64ToRune))
-	reg.Register(Int32, convert.ToAnyAny(convert.Float64ToInt32))
-	reg.Register(Int64, convert.ToAnyAny(convert.Float64ToInt64))
-	reg.Register(Int, convert.ToAnyAny(convert.Float64ToInt))
+	registerNumber[int8](reg, Int8, intConverter(convert.AnyToInt8))
+	registerNumber[int16](reg, Int16, intConverter(convert.AnyToInt16))
+	registerNumber[rune](reg, Rune, intConverter(convert.AnyToRune))
+	registerNumber[int32](reg, Int32, intConverter(convert.AnyToInt32))
+	registerNumber[int64](reg, Int64, intConverter(convert.AnyToInt64))
+	registerNumber[int](reg, Int, intConverter(convert.AnyToInt))
 
-	reg.Register(Float32, convert.ToAnyAny(convert.Float64ToFloat32))
-	reg.Register(Float64, convert.ToAnyAny(convert.Float64ToFloat64))
+	registerNumber[float32](reg, Float32, floatConverter(convert.AnyToFloat32))
+	registerNumber[float64](reg, Float64, floatConverter(convert.AnyToFloat64))
 
 	cnv := convert.StringToTime(time.RFC3339Nano)
-	reg.Register(Time, convert.ToAnyAny(cnv))
-	reg.Register(Duration, convert.ToAnyAny(convert.StringToDuration))
+	registerAs[time.Time](reg, Time, convert.ToAnyAny(cnv))
+	enc := TimeToString(time.RFC3339Nano)
+	reg.RegisterEncoder(Time, convert.ToAnyAny(enc))
+	registerAs[time.Duration](
+		reg,
+		Duration,
+		convert.ToAnyAny(convert.StringToDuration),
+	)
+	reg.RegisterEncoder(Duration, convert.ToAnyAny(DurationToString))
 
-	reg.Register(String, convert.ToAnyAny(convert.StringToString))
-	reg.Register(Bool, convert.ToAnyAny(convert.BoolToBool))
+	registerAs[string](reg, String, convert.ToAnyAny(convert.StringToString))
+	registerAs[bool](reg, Bool, convert.ToAnyAny(convert.BoolToBool))
 
 	reg.Register(Nil, NilConverter)
+
+	_ = reg.update(func(snap *snapshot) error {
+		snap.app = maps.Clone(appenders)
+		return nil
+	})
 	return reg
 }
 
+// registerAs registers the converter and the Go type T it returns.
+func registerAs[T any](reg *Registry, name string, cnv convert.AnyToAny) {
+	reg.RegisterType(name, reflect.TypeFor[T](), cnv)
+}
+
+// registerNumber registers the converter accepting [json.Number] values and
+// the Go type T it returns.
+func registerNumber[T any](reg *Registry, name string, cnv convert.AnyToAny) {
+	reg.RegisterNumber(name, reflect.TypeFor[T](), cnv)
+}
+
 // Value represents a value and its type.
 type Value struct {
-	typ string // Name of the type.
-	val any    // The value to encode.
+	typ  string     // Name of the type.
+	val  any        // The value to encode.
+	lazy *lazyValue // Not yet converted value (lazy decoding mode).
 }
 
 // New returns new instance of [Value] for the given value. The type name is
-// set to the name returned from `reflect.TypeFor[T]().String()`.
+// set to the name returned from `reflect.TypeFor[T]().String()`. For types
+// registered under other names, for example, structs registered with
+// [RegisterStruct], use [NewValue].
 func New[T any](value T) *Value {
 	return &Value{typ: reflect.TypeFor[T]().String(), val: value}
 }
 
 // NewValue works like [New], but it supports untyped nil as the value and
-// checks if the type has a registered converter. Returns error when the type
-// has no registered converter.
+// checks if the type has a registered converter. The type name is the one
+// registered for the Go type of the value, so types registered under custom
+// names are supported. Returns error when the type has no registered
+// converter, or it's not allowed by the type filter (see [WithAllowedTypes]).
+// Typed nil pointers keep their pointer type.
 func NewValue(val any, opts ...Option) (*Value, error) {
+	def := newOptions(opts...)
 	if val == nil {
+		if err := def.allowed(Nil); err != nil {
+			return nil, err
+		}
 		return &Value{typ: Nil, val: nil}, nil
 	}
-	def := &Options{reg: registry}
-	for _, opt := range opts {
-		opt(def)
+	rt := reflect.TypeOf(val)
+	typ := def.reg.typeName(rt)
+	if typ == "" {
+		typ = rt.String()
+	}
+	if err := def.allowed(typ); err != nil {
+		return nil, err
 	}
-	typ := reflect.TypeOf(val).String()
 	if cnv := def.reg.Converter(typ); cnv == nil {
 		return nil, fmt.Errorf("%w: %s", convert.ErrUnsType, typ)
 	}
@@ -118,18 +214,76 @@ func NewValue(val any, opts ...Option) (*Value, error) {
 func (val *Value) GoTypeName() string { return val.typ }
 
 // GoValue returns the underlying Go value.
-func (val *Value) GoValue() any { return val.val }
+//
+// For values decoded with the [WithLazy] option, the value is converted on the
+// first call. Returns nil when the conversion fails, call [Value.Decode] to get
+// the error.
+func (val *Value) GoValue() any {
+	v, _ := val.Decode()
+	return v
+}
 
-// Map returns map representation of the [Value].
-func (val *Value) Map() map[string]any {
-	return map[string]any{"type": val.typ, "value": val.val}
+// Decode returns the underlying Go value. For values decoded with the
+// [WithLazy] option, the value is converted with the converter registered for
+// its type on the first call, and the result (or the error) is cached. It is
+// safe to call it from multiple goroutines. For other values, it never
+// returns an error.
+func (val *Value) Decode() (any, error) {
+	if val.lazy != nil {
+		return val.lazy.decode()
+	}
+	return val.val, nil
+}
+
+// Map returns map representation of the [Value]. The value is encoded with
+// the encoder registered for the type name. When the encoder fails, the raw
+// value is used, call [Value.Encode] to get the error. For lazily decoded
+// values, the value is the raw JSON. By default, the package-level registry
+// is used, use [WithRegistry] to provide a custom one.
+func (val *Value) Map(opts ...Option) map[string]any {
+	def := newOptions(opts...)
+	v, err := val.encode(def.reg)
+	if err != nil {
+		v = val.val
+	}
+	return map[string]any{"type": val.typ, "value": v}
 }
 
+// Encode works like [Value.Map], but returns an error when the encoder
+// registered for the type name fails.
+func (val *Value) Encode(opts ...Option) (map[string]any, error) {
+	def := newOptions(opts...)
+	v, err := val.encode(def.reg)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"type": val.typ, "value": v}, nil
+}
+
+// MarshalJSON uses the package-level registry. To marshal with a custom
+// registry, call [Marshal] directly.
 func (val *Value) MarshalJSON() ([]byte, error) {
-	if val == nil || val.typ == "" {
-		return nil, convert.ErrInvValue
+	return Marshal(registry, val)
+}
+
+// encode encodes the value with the encoder registered for the type name.
+// Returns the raw value if there is no encoder, or the value is nil. Values
+// of unknown types and lazily decoded values are encoded as their raw JSON.
+func (val *Value) encode(reg *Registry) (any, error) {
+	if val.lazy != nil {
+		return val.lazy.raw, nil
+	}
+	if val.val == nil {
+		return nil, nil
 	}
-	return json.Marshal(val.Map())
+	if u, ok := val.val.(*UnknownValue); ok {
+		return u.value, nil
+	}
+	enc := reg.Encoder(val.typ)
+	if enc == nil {
+		return val.val, nil
+	}
+	return enc(val.val)
 }
 
 // UnmarshalJSON uses the package-level registry. To unmarshal with a custom
@@ -141,45 +295,65 @@ func (val *Value) UnmarshalJSON(bytes []byte) error {
 // FromMap constructs an instance of [Value] from its map representation. It
 // expects the map to have the same structure as the one returned from the
 // [Value.Map] method.
-func FromMap(m map[string]any) (val *Value, err error) {
-	var v any
+//
+// Values already of the Go type for the type name are used as they are. Other
+// values are converted using the converter registered for the type name, so
+// maps decoded from JSON to map[string]any (where numbers are float64) are
+// supported. By default, the package-level registry is used, use
+// [WithRegistry] to provide a custom one. Use [WithAllowedTypes] or
+// [WithTypeFilter] to limit the accepted types.
+func FromMap(m map[string]any, opts ...Option) (*Value, error) {
+	var v, t any
 	var ok bool
 
 	if v, ok = keyValue("value", m); !ok {
 		format := "FromMap: missing value field: %w"
 		return nil, fmt.Errorf(format, convert.ErrInvFormat)
 	}
-	if val, err = NewValue(v); err != nil {
-		return nil, fmt.Errorf("FromMap: %w", err)
-	}
-
-	if v, ok = keyValue("type", m); !ok {
+	if t, ok = keyValue("type", m); !ok {
 		format := "FromMap: missing type field: %w"
 		return nil, fmt.Errorf(format, convert.ErrInvFormat)
 	}
 
 	var typ string
-	if typ, ok = v.(string); !ok {
+	if typ, ok = t.(string); !ok {
 		format := "FromMap: type field: %w"
 		return nil, fmt.Errorf(format, convert.ErrInvFormat)
 	}
 
-	if typ != val.typ {
-		format := "FromMap: types do not match: %w"
-		return nil, fmt.Errorf(format, convert.ErrInvValue)
+	def := newOptions(opts...)
+	if err := def.allowed(typ); err != nil {
+		return nil, fmt.Errorf("FromMap: %w", err)
 	}
-	return val, nil
+	cnv := def.reg.decoder(typ)
+	if cnv == nil {
+		return nil, fmt.Errorf("FromMap: %w: %s", convert.ErrUnsType, typ)
+	}
+	if rt := reflect.TypeOf(v); rt != nil {
+		if rt == def.reg.GoType(typ) || rt.String() == typ {
+			return &Value{typ: typ, val: v}, nil
+		}
+	}
+	val, err := cnv(v)
+	if err != nil {
+		return nil, fmt.Errorf("FromMap: %w", err)
+	}
+	return &Value{typ: typ, val: val}, nil
 }
 
 // AsValue converts a map in the format returned by [Value.Map] into a [Value].
 // If v is already a *Value, it returns that value directly. Returns error if
-// conversion is not possible.
-func AsValue(v any) (*Value, error) {
+// conversion is not possible, or the type is not allowed by the type filter
+// (see [WithAllowedTypes]). The options are passed to [FromMap].
+func AsValue(v any, opts ...Option) (*Value, error) {
 	if val, ok := v.(*Value); ok {
+		if err := newOptions(opts...).allowed(val.typ); err != nil {
+			return nil, fmt.Errorf("AsValue: %w", err)
+		}
 		return val, nil
 	}
 	if val, ok := v.(map[string]any); ok {
-		return FromMap(val)
+		return FromMap(val, opts...)
 	}
 	return nil, fmt.Errorf("AsValue: %w", convert.ErrInvType)
 }
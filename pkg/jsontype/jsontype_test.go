@@ -4,6 +4,9 @@
 package jsontype
 
 import (
+	"encoding/json"
+	"math"
+	"reflect"
 	"testing"
 	"time"
 
@@ -17,7 +20,7 @@ import (
 
 func Test_init(t *testing.T) {
 	assert.NotNil(t, registry)
-	assert.Len(t, 19, registry.reg)
+	assert.Len(t, 19, registry.snap.Load().reg)
 }
 
 func Test_Register(t *testing.T) {
@@ -31,7 +34,7 @@ func Test_Register(t *testing.T) {
 
 		// --- Then ---
 		assert.Nil(t, have)
-		assert.Same(t, cnv, registry.reg[name])
+		assert.Same(t, cnv, registry.snap.Load().reg[name])
 	})
 
 	t.Run("overwrite existing converter", func(t *testing.T) {
@@ -59,7 +62,170 @@ func Test_Register(t *testing.T) {
 
 		// --- Then ---
 		assert.Nil(t, have)
-		assert.Same(t, cnv, registry.reg[name])
+		assert.Same(t, cnv, registry.snap.Load().reg[name])
+	})
+}
+
+func Test_RegisterStrict(t *testing.T) {
+	t.Run("new converter", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(any) (any, error) { return nil, nil }
+		name := t.Name()
+
+		// --- When ---
+		err := RegisterStrict(name, cnv)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Same(t, cnv, registry.snap.Load().reg[name])
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		// --- Given ---
+		cnv0 := func(any) (any, error) { return nil, nil }
+		cnv1 := func(any) (any, error) { return nil, nil }
+		name := t.Name()
+		Register(name, cnv0)
+
+		// --- When ---
+		err := RegisterStrict(name, cnv1)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrConflict, err)
+		assert.Same(t, cnv0, registry.snap.Load().reg[name])
+	})
+}
+
+func Test_MustRegister(t *testing.T) {
+	t.Run("new converter", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(any) (any, error) { return nil, nil }
+		name := t.Name()
+
+		// --- When ---
+		MustRegister(name, cnv)
+
+		// --- Then ---
+		assert.Same(t, cnv, registry.snap.Load().reg[name])
+	})
+
+	t.Run("conflict panics", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(any) (any, error) { return nil, nil }
+
+		// --- When ---
+		fn := func() { MustRegister(Int, cnv) }
+
+		// --- Then ---
+		assert.PanicContain(t, "type name already registered: int", fn)
+	})
+}
+
+func Test_SetStrictRegister(t *testing.T) {
+	// --- Given ---
+	orig := registry
+	t.Cleanup(func() { registry = orig })
+	registry = DefaultRegistry()
+	cnv := func(value any) (any, error) { return value, nil }
+	Register(Time, cnv)
+
+	// --- When ---
+	err := SetStrictRegister()
+
+	// --- Then ---
+	assert.ErrorIs(t, ErrConflict, err)
+	assert.True(t, registry.strict.Load())
+	assert.False(t, orig.strict.Load())
+	assert.Panic(t, func() { Register(Time, cnv) })
+	assert.ErrorIs(t, ErrConflict, RegisterStrict(Int, cnv))
+}
+
+func Test_Freeze(t *testing.T) {
+	// --- Given ---
+	orig := registry
+	t.Cleanup(func() { registry = orig })
+	registry = DefaultRegistry()
+
+	// --- When ---
+	Freeze()
+
+	// --- Then ---
+	assert.True(t, IsFrozen())
+	assert.True(t, registry.IsFrozen())
+	assert.False(t, orig.IsFrozen())
+}
+
+func Test_RegisterType(t *testing.T) {
+	t.Run("new converter", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(any) (any, error) { return nil, nil }
+		name := t.Name()
+
+		// --- When ---
+		have := RegisterType(name, reflect.TypeFor[int](), cnv)
+
+		// --- Then ---
+		assert.Nil(t, have)
+		assert.Same(t, cnv, registry.snap.Load().reg[name])
+		assert.Equal(t, reflect.TypeFor[int](), registry.snap.Load().typ[name])
+	})
+
+	t.Run("nil converter is nop", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(any) (any, error) { return nil, nil }
+		name := t.Name()
+		RegisterType(name, reflect.TypeFor[int](), cnv)
+
+		// --- When ---
+		have := RegisterType(name, reflect.TypeFor[uint](), nil)
+
+		// --- Then ---
+		assert.Nil(t, have)
+		assert.Same(t, cnv, registry.snap.Load().reg[name])
+		assert.Equal(t, reflect.TypeFor[int](), registry.snap.Load().typ[name])
+	})
+}
+
+func Test_RegisterNumber(t *testing.T) {
+	// --- Given ---
+	cnv := func(any) (any, error) { return nil, nil }
+	name := t.Name()
+
+	// --- When ---
+	have := RegisterNumber(name, reflect.TypeFor[int](), cnv)
+
+	// --- Then ---
+	assert.Nil(t, have)
+	assert.Same(t, cnv, registry.snap.Load().reg[name])
+	assert.True(t, registry.snap.Load().num[name])
+}
+
+func Test_RegisterEncoder(t *testing.T) {
+	t.Run("new encoder", func(t *testing.T) {
+		// --- Given ---
+		enc := func(any) (any, error) { return nil, nil }
+		name := t.Name()
+
+		// --- When ---
+		have := RegisterEncoder(name, enc)
+
+		// --- Then ---
+		assert.Nil(t, have)
+		assert.Same(t, enc, registry.snap.Load().enc[name])
+	})
+
+	t.Run("nil encoder is nop", func(t *testing.T) {
+		// --- Given ---
+		enc := func(any) (any, error) { return nil, nil }
+		name := t.Name()
+		RegisterEncoder(name, enc)
+
+		// --- When ---
+		have := RegisterEncoder(name, nil)
+
+		// --- Then ---
+		assert.Nil(t, have)
+		assert.Same(t, enc, registry.snap.Load().enc[name])
 	})
 }
 
@@ -68,7 +234,7 @@ func Test_DefaultRegistry(t *testing.T) {
 	have := DefaultRegistry()
 
 	// --- Then ---
-	assert.Len(t, 19, have.reg)
+	assert.Len(t, 19, have.snap.Load().reg)
 
 	assert.NotNil(t, have.Converter(Int))
 	assert.NotNil(t, have.Converter(Int16))
@@ -92,6 +258,15 @@ func Test_DefaultRegistry(t *testing.T) {
 	assert.NotNil(t, have.Converter(Time))
 	assert.NotNil(t, have.Converter(Duration))
 	assert.NotNil(t, have.Converter(Nil))
+
+	assert.Len(t, 18, have.snap.Load().typ)
+	assert.Equal(t, reflect.TypeFor[uint64](), have.GoType(Uint64))
+	assert.Equal(t, reflect.TypeFor[time.Time](), have.GoType(Time))
+	assert.Nil(t, have.GoType(Nil))
+
+	assert.Len(t, 2, have.snap.Load().enc)
+	assert.NotNil(t, have.Encoder(Time))
+	assert.NotNil(t, have.Encoder(Duration))
 }
 
 func Test_New(t *testing.T) {
@@ -301,6 +476,16 @@ func Test_NewValue(t *testing.T) {
 		assert.Nil(t, have.val)
 	})
 
+	t.Run("typed nil pointer", func(t *testing.T) {
+		// --- When ---
+		have, err := NewValue((*int)(nil))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "*int", have.typ)
+		assert.SameType(t, (*int)(nil), have.val)
+	})
+
 	t.Run("registered type", func(t *testing.T) {
 		// --- When ---
 		have, err := NewValue(42)
@@ -326,6 +511,53 @@ func Test_NewValue(t *testing.T) {
 		assert.Equal(t, MyType(42), have.val)
 	})
 
+	t.Run("struct registered under custom name", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+		must.Nil(RegisterStruct[TCircle]("circle", WithRegistry(reg)))
+
+		// --- When ---
+		have, err := NewValue(TCircle{R: 1}, WithRegistry(reg))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "circle", have.typ)
+		data := must.Value(Marshal(reg, have))
+		assert.Equal(t, `{"type":"circle","value":{"r":1}}`, string(data))
+	})
+
+	t.Run("composite of custom names", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+		must.Nil(RegisterStruct[TCircle]("circle", WithRegistry(reg)))
+
+		// --- When ---
+		have, err := NewValue([]TCircle{{R: 1}}, WithRegistry(reg))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "[]circle", have.typ)
+	})
+
+	t.Run("composite type", func(t *testing.T) {
+		// --- When ---
+		have, err := NewValue([]uint64{42})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "[]uint64", have.typ)
+		assert.Equal(t, []uint64{42}, have.val)
+	})
+
+	t.Run("allowed type", func(t *testing.T) {
+		// --- When ---
+		have, err := NewValue(42, WithAllowedTypes(Int))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, Int, have.typ)
+	})
+
 	t.Run("error - unsupported type", func(t *testing.T) {
 		// --- When ---
 		have, err := NewValue(MyType(42))
@@ -335,6 +567,25 @@ func Test_NewValue(t *testing.T) {
 		assert.ErrorEqual(t, "unsupported type: jsontype.MyType", err)
 		assert.Nil(t, have)
 	})
+
+	t.Run("error - type not allowed", func(t *testing.T) {
+		// --- When ---
+		have, err := NewValue(uint(42), WithAllowedTypes(Int))
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrTypeNotAllowed, err)
+		assert.ErrorEqual(t, "type not allowed: uint", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - nil not allowed", func(t *testing.T) {
+		// --- When ---
+		have, err := NewValue(nil, WithAllowedTypes(Int))
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrTypeNotAllowed, err)
+		assert.Nil(t, have)
+	})
 }
 
 func Test_Value_GoTypeName(t *testing.T) {
@@ -349,26 +600,177 @@ func Test_Value_GoTypeName(t *testing.T) {
 }
 
 func Test_Value_GoValue(t *testing.T) {
-	// --- Given ---
-	val := &Value{typ: String, val: "abc"}
+	t.Run("value", func(t *testing.T) {
+		// --- Given ---
+		val := &Value{typ: String, val: "abc"}
 
-	// --- When ---
-	have := val.GoValue()
+		// --- When ---
+		have := val.GoValue()
 
-	// --- Then ---
-	assert.Equal(t, "abc", have)
+		// --- Then ---
+		assert.Equal(t, "abc", have)
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint64", "value": 18446744073709551615}`
+		val := must.Value(UnmarshalValue([]byte(data), WithLazy()))
+
+		// --- When ---
+		have := val.GoValue()
+
+		// --- Then ---
+		assert.Equal(t, uint64(1<<64-1), have)
+	})
+
+	t.Run("lazy error", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint8", "value": 256}`
+		val := must.Value(UnmarshalValue([]byte(data), WithLazy()))
+
+		// --- When ---
+		have := val.GoValue()
+
+		// --- Then ---
+		assert.Nil(t, have)
+	})
+}
+
+func Test_Value_Decode(t *testing.T) {
+	t.Run("value", func(t *testing.T) {
+		// --- Given ---
+		val := &Value{typ: String, val: "abc"}
+
+		// --- When ---
+		have, err := val.Decode()
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", have)
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "time.Duration", "value": "1s"}`
+		val := must.Value(UnmarshalValue([]byte(data), WithLazy()))
+
+		// --- When ---
+		have, err := val.Decode()
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, time.Second, have)
+	})
+
+	t.Run("lazy error", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint8", "value": 256}`
+		val := must.Value(UnmarshalValue([]byte(data), WithLazy()))
+
+		// --- When ---
+		have, err := val.Decode()
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvRange, err)
+		assert.ErrorContain(t, "jsontype: ", err)
+		assert.Nil(t, have)
+	})
 }
 
 func Test_Value_Map(t *testing.T) {
-	// --- Given ---
-	val := &Value{typ: Uint, val: uint(42)}
+	t.Run("without encoder", func(t *testing.T) {
+		// --- Given ---
+		val := &Value{typ: Uint, val: uint(42)}
 
-	// --- When ---
-	have := val.Map()
+		// --- When ---
+		have := val.Map()
 
-	// --- Then ---
-	want := map[string]any{"type": "uint", "value": uint(42)}
-	assert.Equal(t, want, have)
+		// --- Then ---
+		want := map[string]any{"type": "uint", "value": uint(42)}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("with encoder", func(t *testing.T) {
+		// --- Given ---
+		val := &Value{typ: Duration, val: time.Minute}
+
+		// --- When ---
+		have := val.Map()
+
+		// --- Then ---
+		want := map[string]any{"type": "time.Duration", "value": "1m0s"}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("use custom registry", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+		reg.RegisterEncoder(Uint, func(any) (any, error) { return 44, nil })
+		val := &Value{typ: Uint, val: uint(42)}
+
+		// --- When ---
+		have := val.Map(WithRegistry(reg))
+
+		// --- Then ---
+		want := map[string]any{"type": "uint", "value": 44}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("encoder error uses raw value", func(t *testing.T) {
+		// --- Given ---
+		val := &Value{typ: Duration, val: 42}
+
+		// --- When ---
+		have := val.Map()
+
+		// --- Then ---
+		want := map[string]any{"type": "time.Duration", "value": 42}
+		assert.Equal(t, want, have)
+	})
+}
+
+func Test_Value_Encode(t *testing.T) {
+	t.Run("with encoder", func(t *testing.T) {
+		// --- Given ---
+		val := &Value{typ: Duration, val: time.Minute}
+
+		// --- When ---
+		have, err := val.Encode()
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := map[string]any{"type": "time.Duration", "value": "1m0s"}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("use custom registry", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+		reg.RegisterEncoder(Uint, func(any) (any, error) { return 44, nil })
+		val := &Value{typ: Uint, val: uint(42)}
+
+		// --- When ---
+		have, err := val.Encode(WithRegistry(reg))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := map[string]any{"type": "uint", "value": 44}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("error - encoder", func(t *testing.T) {
+		// --- Given ---
+		val := &Value{typ: Duration, val: 42}
+
+		// --- When ---
+		have, err := val.Encode()
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "invalid type: expected time.Duration got int"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
 }
 
 func Test_Value_MarshalJSON(t *testing.T) {
@@ -384,6 +786,18 @@ func Test_Value_MarshalJSON(t *testing.T) {
 		assert.JSON(t, `{"type":"int","value":42}`, string(have))
 	})
 
+	t.Run("success time.Duration", func(t *testing.T) {
+		// --- Given ---
+		val := New(time.Minute)
+
+		// --- When ---
+		have, err := val.MarshalJSON()
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.JSON(t, `{"type":"time.Duration","value":"1m0s"}`, string(have))
+	})
+
 	t.Run("success nil", func(t *testing.T) {
 		// --- Given ---
 		val := must.Value(NewValue(nil))
@@ -490,7 +904,20 @@ func Test_FromMap(t *testing.T) {
 		assert.Nil(t, have)
 	})
 
-	t.Run("error - unsupported value key type", func(t *testing.T) {
+	t.Run("error - type not allowed", func(t *testing.T) {
+		// --- Given ---
+		m := map[string]any{"type": "uint", "value": 42.0}
+
+		// --- When ---
+		have, err := FromMap(m, WithAllowedTypes(Int, Float64))
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrTypeNotAllowed, err)
+		assert.ErrorEqual(t, "FromMap: type not allowed: uint", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - value not convertible", func(t *testing.T) {
 		// --- Given ---
 		m := map[string]any{"type": "uint", "value": Value{}}
 
@@ -498,8 +925,9 @@ func Test_FromMap(t *testing.T) {
 		have, err := FromMap(m)
 
 		// --- Then ---
-		assert.ErrorIs(t, convert.ErrUnsType, err)
-		assert.ErrorEqual(t, "FromMap: unsupported type: jsontype.Value", err)
+		assert.ErrorIs(t, convert.ErrUnkConv, err)
+		wMsg := "FromMap: conversion undefined: from jsontype.Value to uint"
+		assert.ErrorEqual(t, wMsg, err)
 		assert.Nil(t, have)
 	})
 
@@ -529,7 +957,7 @@ func Test_FromMap(t *testing.T) {
 		assert.Nil(t, have)
 	})
 
-	t.Run("error - types do not match", func(t *testing.T) {
+	t.Run("value converted to the declared type", func(t *testing.T) {
 		// --- Given ---
 		m := map[string]any{"type": "int", "value": uint(42)}
 
@@ -537,8 +965,83 @@ func Test_FromMap(t *testing.T) {
 		have, err := FromMap(m)
 
 		// --- Then ---
-		assert.ErrorIs(t, convert.ErrInvValue, err)
-		assert.ErrorEqual(t, "FromMap: types do not match: invalid value", err)
+		assert.NoError(t, err)
+		assert.Equal(t, Int, have.typ)
+		assert.Equal(t, 42, have.val)
+	})
+
+	t.Run("map decoded from JSON", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint", "value": 42}`
+		m := make(map[string]any)
+		must.Nil(json.Unmarshal([]byte(data), &m))
+
+		// --- When ---
+		have, err := FromMap(m)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, Uint, have.typ)
+		assert.Equal(t, uint(42), have.val)
+	})
+
+	t.Run("composite map decoded from JSON", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "map[string][]uint16", "value": {"A": [1, 2]}}`
+		m := make(map[string]any)
+		must.Nil(json.Unmarshal([]byte(data), &m))
+
+		// --- When ---
+		have, err := FromMap(m)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "map[string][]uint16", have.typ)
+		assert.Equal(t, map[string][]uint16{"A": {1, 2}}, have.val)
+	})
+
+	t.Run("use custom registry", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value float64) (time.Duration, error) {
+			return time.Duration(value) * time.Second, nil
+		}
+		reg := NewRegistry()
+		reg.Register("seconds", convert.ToAnyAny(cnv))
+		m := map[string]any{"type": "seconds", "value": 42.0}
+
+		// --- When ---
+		have, err := FromMap(m, WithRegistry(reg))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "seconds", have.typ)
+		assert.Equal(t, 42*time.Second, have.val)
+	})
+
+	t.Run("error - unsupported type", func(t *testing.T) {
+		// --- Given ---
+		m := map[string]any{"type": "unknown", "value": 42.0}
+
+		// --- When ---
+		have, err := FromMap(m)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		assert.ErrorEqual(t, "FromMap: unsupported type: unknown", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - value out of range", func(t *testing.T) {
+		// --- Given ---
+		m := map[string]any{"type": "uint8", "value": 256.0}
+
+		// --- When ---
+		have, err := FromMap(m)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvRange, err)
+		wMsg := "FromMap: value out of range: from float64 to uint8"
+		assert.ErrorEqual(t, wMsg, err)
 		assert.Nil(t, have)
 	})
 
@@ -553,6 +1056,60 @@ func Test_FromMap(t *testing.T) {
 	})
 }
 
+func Test_FromMap_Value_Map_round_trip(t *testing.T) {
+	t.Run("with encoder", func(t *testing.T) {
+		// --- Given ---
+		val := New(map[string][]time.Duration{"A": {time.Second}})
+
+		// --- When ---
+		have, err := FromMap(val.Map())
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, val.typ, have.typ)
+		assert.Equal(t, val.val, have.val)
+	})
+
+	t.Run("without encoder", func(t *testing.T) {
+		// --- Given ---
+		type Money struct{ Amount int }
+		cnv := func(value any) (any, error) {
+			m, ok := value.(map[string]any)
+			if !ok {
+				return nil, convert.ErrInvType
+			}
+			return Money{Amount: int(m["Amount"].(float64))}, nil
+		}
+		reg := NewRegistry()
+		reg.Register("jsontype.Money", cnv)
+		val := New(Money{Amount: 42})
+
+		// --- When ---
+		have, err := FromMap(val.Map(WithRegistry(reg)), WithRegistry(reg))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "jsontype.Money", have.typ)
+		assert.Equal(t, Money{Amount: 42}, have.val)
+	})
+
+	t.Run("registered Go type", func(t *testing.T) {
+		// --- Given ---
+		type Money struct{ Amount int }
+		cnv := func(value any) (any, error) { return nil, convert.ErrInvType }
+		reg := NewRegistry()
+		reg.RegisterType("money", reflect.TypeFor[Money](), cnv)
+		val := &Value{typ: "money", val: Money{Amount: 42}}
+
+		// --- When ---
+		have, err := FromMap(val.Map(WithRegistry(reg)), WithRegistry(reg))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, Money{Amount: 42}, have.val)
+	})
+}
+
 func Test_AsValue(t *testing.T) {
 	t.Run("instance of Value", func(t *testing.T) {
 		// --- Given ---
@@ -580,6 +1137,47 @@ func Test_AsValue(t *testing.T) {
 		assert.Equal(t, uint(42), have.val)
 	})
 
+	t.Run("map use custom registry", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+		reg.Register("custom", cnv)
+		m := map[string]any{"type": "custom", "value": 42.0}
+
+		// --- When ---
+		have, err := AsValue(m, WithRegistry(reg))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "custom", have.typ)
+		assert.Equal(t, 42.0, have.val)
+	})
+
+	t.Run("error - value type not allowed", func(t *testing.T) {
+		// --- Given ---
+		val := New(uint32(42))
+
+		// --- When ---
+		have, err := AsValue(val, WithAllowedTypes(Uint))
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrTypeNotAllowed, err)
+		assert.ErrorEqual(t, "AsValue: type not allowed: uint32", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - map type not allowed", func(t *testing.T) {
+		// --- Given ---
+		m := map[string]any{"type": "uint", "value": uint(42)}
+
+		// --- When ---
+		have, err := AsValue(m, WithAllowedTypes(Uint32))
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrTypeNotAllowed, err)
+		assert.Nil(t, have)
+	})
+
 	t.Run("error - not a map", func(t *testing.T) {
 		// --- When ---
 		have, err := AsValue(nil)
@@ -730,3 +1328,75 @@ func Test_Value_UnmarshalJSON_success_tabular(t *testing.T) {
 		})
 	}
 }
+
+func Test_Value_round_trip_integer_boundaries_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		val *Value
+	}{
+		{"int min", New(math.MinInt)},
+		{"int max", New(math.MaxInt)},
+		{"int8 min", New(int8(math.MinInt8))},
+		{"int8 max", New(int8(math.MaxInt8))},
+		{"int16 min", New(int16(math.MinInt16))},
+		{"int16 max", New(int16(math.MaxInt16))},
+		{"int32 min", New(int32(math.MinInt32))},
+		{"int32 max", New(int32(math.MaxInt32))},
+		{"int64 min", New(int64(math.MinInt64))},
+		{"int64 max", New(int64(math.MaxInt64))},
+		{"uint min", New(uint(0))},
+		{"uint max", New(uint(math.MaxUint))},
+		{"uint8 max", New(uint8(math.MaxUint8))},
+		{"uint16 max", New(uint16(math.MaxUint16))},
+		{"uint32 max", New(uint32(math.MaxUint32))},
+		{"uint64 min", New(uint64(0))},
+		{"uint64 max", New(uint64(math.MaxUint64))},
+		{"uint64 float64 safe max + 2", New(uint64(1<<53 + 1))},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- Given ---
+			data := must.Value(json.Marshal(tc.val))
+			have := &Value{}
+
+			// --- When ---
+			err := json.Unmarshal(data, have)
+
+			// --- Then ---
+			assert.NoError(t, err)
+			assert.Equal(t, tc.val.typ, have.typ)
+			assert.Equal(t, tc.val.val, have.val)
+		})
+	}
+}
+
+func Test_Value_UnmarshalJSON_integer_overflow_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		json string
+	}{
+		{"int8 min - 1", `{"type": "int8", "value": -129}`},
+		{"int8 max + 1", `{"type": "int8", "value": 128}`},
+		{"int64 min - 1", `{"type": "int64", "value": -9223372036854775809}`},
+		{"int64 max + 1", `{"type": "int64", "value": 9223372036854775808}`},
+		{"uint8 max + 1", `{"type": "uint8", "value": 256}`},
+		{"uint64 min - 1", `{"type": "uint64", "value": -1}`},
+		{"uint64 max + 1", `{"type": "uint64", "value": 18446744073709551616}`},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- Given ---
+			val := &Value{}
+
+			// --- When ---
+			err := val.UnmarshalJSON([]byte(tc.json))
+
+			// --- Then ---
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+//go:build goexperiment.jsonv2
+
+package jsontype
+
+import (
+	"encoding/json/jsontext"
+	"fmt"
+	"strings"
+
+	"github.com/ctx42/convert/pkg/convert"
+)
+
+// MarshalJSONTo uses the package-level registry. It works like
+// [Value.MarshalJSON], but it writes the value directly to the encoder.
+func (val *Value) MarshalJSONTo(enc *jsontext.Encoder) error {
+	buf := bufPool.Get().(*[]byte)
+	out, err := AppendJSON((*buf)[:0], registry, val)
+	if err == nil {
+		if err = enc.WriteValue(out); err != nil {
+			err = fmt.Errorf("jsontype: %w", err)
+		}
+	}
+	if cap(out) <= maxPooledBuf {
+		*buf = out[:0]
+		bufPool.Put(buf)
+	}
+	return err
+}
+
+// UnmarshalJSONFrom uses the package-level registry. It works like
+// [Value.UnmarshalJSON], but it reads the value directly from the decoder.
+func (val *Value) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	typ, raw, err := readEnvelope(dec)
+	if err != nil {
+		return fmt.Errorf("jsontype: %w", err)
+	}
+	cnv := registry.decoder(typ)
+	if cnv == nil {
+		return fmt.Errorf("%w: %s", convert.ErrUnsType, typ)
+	}
+	v, err := decodeValue(cnv, raw)
+	if err != nil {
+		return err
+	}
+	val.typ = typ
+	val.val = v
+	val.lazy = nil
+	return nil
+}
+
+// readEnvelope reads the type name and the raw JSON of the value from the
+// decoder. The same as [json.Unmarshal], it matches field names case
+// insensitively, ignores unknown fields and treats null as an empty object.
+func readEnvelope(dec *jsontext.Decoder) (string, []byte, error) {
+	tok, err := dec.ReadToken()
+	if err != nil {
+		return "", nil, err
+	}
+	switch tok.Kind() {
+	case 'n':
+		return "", nil, nil
+	case '{':
+	default:
+		format := "%w: expected object got %s"
+		return "", nil, fmt.Errorf(format, convert.ErrInvType, tok.Kind())
+	}
+
+	var typ string
+	var raw []byte
+	for dec.PeekKind() != '}' {
+		name, err := dec.ReadToken()
+		if err != nil {
+			return "", nil, err
+		}
+		switch {
+		case strings.EqualFold(name.String(), "type"):
+			if tok, err = dec.ReadToken(); err != nil {
+				return "", nil, err
+			}
+			switch tok.Kind() {
+			case '"':
+				typ = tok.String()
+			case 'n':
+			default:
+				format := "%w: field type: expected string got %s"
+				err = fmt.Errorf(format, convert.ErrInvType, tok.Kind())
+				return "", nil, err
+			}
+
+		case strings.EqualFold(name.String(), "value"):
+			v, err := dec.ReadValue()
+			if err != nil {
+				return "", nil, err
+			}
+			raw = v.Clone()
+
+		default:
+			if err = dec.SkipValue(); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+	if _, err = dec.ReadToken(); err != nil {
+		return "", nil, err
+	}
+	return typ, raw, nil
+}
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+//go:build goexperiment.jsonv2
+
+package jsontype
+
+import (
+	"bytes"
+	"encoding/json/jsontext"
+	jsonv2 "encoding/json/v2"
+	"strings"
+	"testing"
+
+	"github.com/ctx42/convert/pkg/convert"
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+func init() {
+	codecs = append(codecs, codec{
+		name: "v2",
+		marshal: func(v any) ([]byte, error) {
+			return jsonv2.Marshal(v)
+		},
+		unmarshal: func(data []byte, v any) error {
+			return jsonv2.Unmarshal(data, v)
+		},
+	})
+}
+
+func Test_Value_MarshalJSONTo(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		buf := &bytes.Buffer{}
+		enc := jsontext.NewEncoder(buf)
+
+		// --- When ---
+		err := New(uint64(1<<64 - 1)).MarshalJSONTo(enc)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := `{"type":"uint64","value":18446744073709551615}` + "\n"
+		assert.Equal(t, want, buf.String())
+	})
+
+	t.Run("error - encoder", func(t *testing.T) {
+		// --- Given ---
+		enc := jsontext.NewEncoder(errWriter{})
+
+		// --- When ---
+		err := New(uint(1)).MarshalJSONTo(enc)
+
+		// --- Then ---
+		assert.ErrorContain(t, "jsontype: ", err)
+	})
+}
+
+func Test_Value_UnmarshalJSONFrom(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint64", "value": 18446744073709551615}`
+		dec := jsontext.NewDecoder(strings.NewReader(data))
+
+		// --- When ---
+		have := &Value{}
+		err := have.UnmarshalJSONFrom(dec)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, New(uint64(1<<64-1)), have)
+	})
+
+	t.Run("error - syntax", func(t *testing.T) {
+		// --- Given ---
+		dec := jsontext.NewDecoder(strings.NewReader(`{"type": }`))
+
+		// --- When ---
+		err := (&Value{}).UnmarshalJSONFrom(dec)
+
+		// --- Then ---
+		assert.ErrorContain(t, "jsontype: ", err)
+	})
+}
+
+func Test_readEnvelope(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		data := `{"other": {"a": [1]}, "value": [1, 2], "type": "x"}`
+		dec := jsontext.NewDecoder(strings.NewReader(data))
+
+		// --- When ---
+		typ, raw, err := readEnvelope(dec)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "x", typ)
+		assert.Equal(t, `[1, 2]`, string(raw))
+	})
+
+	t.Run("null type", func(t *testing.T) {
+		// --- Given ---
+		dec := jsontext.NewDecoder(strings.NewReader(`{"type": null}`))
+
+		// --- When ---
+		typ, raw, err := readEnvelope(dec)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Empty(t, typ)
+		assert.Nil(t, raw)
+	})
+
+	t.Run("error - not an object", func(t *testing.T) {
+		// --- Given ---
+		dec := jsontext.NewDecoder(strings.NewReader(`[]`))
+
+		// --- When ---
+		_, _, err := readEnvelope(dec)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		assert.ErrorEqual(t, "invalid type: expected object got [", err)
+	})
+
+	t.Run("error - type not a string", func(t *testing.T) {
+		// --- Given ---
+		dec := jsontext.NewDecoder(strings.NewReader(`{"type": 1}`))
+
+		// --- When ---
+		_, _, err := readEnvelope(dec)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "invalid type: field type: expected string got number"
+		assert.ErrorEqual(t, wMsg, err)
+	})
+}
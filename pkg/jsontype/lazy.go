@@ -0,0 +1,27 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/ctx42/convert/pkg/convert"
+)
+
+// lazyValue represents a value which is converted on first use.
+type lazyValue struct {
+	cnv  convert.AnyToAny // Converter for the value type.
+	raw  json.RawMessage  // Raw JSON of the value.
+	once sync.Once        // Guards the conversion.
+	val  any              // Converted value.
+	err  error            // Conversion error.
+}
+
+// decode converts the value on the first call and returns the cached result
+// on subsequent calls.
+func (l *lazyValue) decode() (any, error) {
+	l.once.Do(func() { l.val, l.err = decodeValue(l.cnv, l.raw) })
+	return l.val, l.err
+}
@@ -0,0 +1,216 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ctx42/convert/pkg/convert"
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
+)
+
+// countingRegistry returns a registry with the "cnt" type which converter
+// counts its calls.
+func countingRegistry(t *testing.T, cnt *atomic.Int32) *Registry {
+	t.Helper()
+	reg := DefaultRegistry()
+	u64 := reg.Converter(Uint64)
+	cnv := func(value any) (any, error) {
+		cnt.Add(1)
+		if value == nil {
+			return nil, convert.ErrInvValue
+		}
+		return u64(value)
+	}
+	reg.Register("cnt", cnv)
+	return reg
+}
+
+func Test_lazyValue_decode(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		cnt := &atomic.Int32{}
+		reg := countingRegistry(t, cnt)
+		lv := &lazyValue{cnv: reg.Converter("cnt"), raw: []byte(`42`)}
+
+		// --- When ---
+		have0, err0 := lv.decode()
+		have1, err1 := lv.decode()
+
+		// --- Then ---
+		assert.NoError(t, err0)
+		assert.NoError(t, err1)
+		assert.Equal(t, uint64(42), have0)
+		assert.Equal(t, uint64(42), have1)
+		assert.Equal(t, int32(1), cnt.Load())
+	})
+
+	t.Run("error is cached", func(t *testing.T) {
+		// --- Given ---
+		cnt := &atomic.Int32{}
+		reg := countingRegistry(t, cnt)
+		lv := &lazyValue{cnv: reg.Converter("cnt"), raw: []byte(`null`)}
+
+		// --- When ---
+		have0, err0 := lv.decode()
+		have1, err1 := lv.decode()
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvValue, err0)
+		assert.Same(t, err0, err1)
+		assert.Nil(t, have0)
+		assert.Nil(t, have1)
+		assert.Equal(t, int32(1), cnt.Load())
+	})
+
+	t.Run("error - invalid JSON", func(t *testing.T) {
+		// --- Given ---
+		lv := &lazyValue{cnv: registry.Converter(Uint), raw: []byte(`{`)}
+
+		// --- When ---
+		have, err := lv.decode()
+
+		// --- Then ---
+		assert.ErrorContain(t, "jsontype: unexpected EOF", err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_WithLazy_decoding(t *testing.T) {
+	t.Run("conversion is deferred", func(t *testing.T) {
+		// --- Given ---
+		cnt := &atomic.Int32{}
+		reg := countingRegistry(t, cnt)
+		data := `{"type": "cnt", "value": 42}`
+
+		// --- When ---
+		val, err := UnmarshalValue([]byte(data), WithRegistry(reg), WithLazy())
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "cnt", val.GoTypeName())
+		assert.Equal(t, int32(0), cnt.Load())
+		assert.Equal(t, uint64(42), val.GoValue())
+		assert.Equal(t, int32(1), cnt.Load())
+	})
+
+	t.Run("conversion error is deferred", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint8", "value": 256}`
+
+		// --- When ---
+		val, err := UnmarshalValue([]byte(data), WithLazy())
+
+		// --- Then ---
+		assert.NoError(t, err)
+		_, err = val.Decode()
+		assert.ErrorIs(t, convert.ErrInvRange, err)
+	})
+
+	t.Run("error - unsupported type", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "unknown", "value": 1}`
+
+		// --- When ---
+		val, err := UnmarshalValue([]byte(data), WithLazy())
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		assert.Nil(t, val)
+	})
+
+	t.Run("marshal without conversion", func(t *testing.T) {
+		// --- Given ---
+		cnt := &atomic.Int32{}
+		reg := countingRegistry(t, cnt)
+		data := `{"type": "cnt", "value": 42}`
+		opts := []Option{WithRegistry(reg), WithLazy()}
+		val := must.Value(UnmarshalValue([]byte(data), opts...))
+
+		// --- When ---
+		have, err := Marshal(reg, val)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.JSON(t, data, string(have))
+		assert.Equal(t, int32(0), cnt.Load())
+	})
+
+	t.Run("map", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint", "value": 42}`
+		val := must.Value(UnmarshalValue([]byte(data), WithLazy()))
+
+		// --- When ---
+		have := val.Map()
+
+		// --- Then ---
+		want := map[string]any{"type": "uint", "value": json.RawMessage(`42`)}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("reused value is reset", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint", "value": 42}`
+		val := must.Value(UnmarshalValue([]byte(data), WithLazy()))
+
+		// --- When ---
+		err := json.Unmarshal([]byte(`{"type": "int", "value": 1}`), val)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Nil(t, val.lazy)
+		assert.Equal(t, 1, val.GoValue())
+	})
+
+	t.Run("decoder", func(t *testing.T) {
+		// --- Given ---
+		cnt := &atomic.Int32{}
+		reg := countingRegistry(t, cnt)
+		data := `{"type": "cnt", "value": 1} {"type": "cnt", "value": 2}`
+		opts := []Option{WithRegistry(reg), WithLazy()}
+		dec := NewDecoder(strings.NewReader(data), opts...)
+
+		// --- When ---
+		val0, val1 := &Value{}, &Value{}
+		must.Nil(dec.Decode(val0))
+		must.Nil(dec.Decode(val1))
+
+		// --- Then ---
+		assert.Equal(t, int32(0), cnt.Load())
+		assert.Equal(t, uint64(2), val1.GoValue())
+		assert.Equal(t, int32(1), cnt.Load())
+	})
+
+	t.Run("concurrent access", func(t *testing.T) {
+		// --- Given ---
+		cnt := &atomic.Int32{}
+		reg := countingRegistry(t, cnt)
+		data := `{"type": "cnt", "value": 42}`
+		opts := []Option{WithRegistry(reg), WithLazy()}
+		val := must.Value(UnmarshalValue([]byte(data), opts...))
+
+		// --- When ---
+		var wg sync.WaitGroup
+		var errs [50]error
+		var vals [50]any
+		for i := range 50 {
+			wg.Go(func() { vals[i], errs[i] = val.Decode() })
+		}
+		wg.Wait()
+
+		// --- Then ---
+		assert.NoError(t, errors.Join(errs[:]...))
+		for _, v := range vals {
+			assert.Equal(t, uint64(42), v)
+		}
+		assert.Equal(t, int32(1), cnt.Load())
+	})
+}
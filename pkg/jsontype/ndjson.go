@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"sync"
+)
+
+// ReadNDJSON returns an iterator over values read from newline-delimited JSON
+// (NDJSON), one value per line. Empty lines are skipped.
+//
+// Errors for invalid lines are yielded with their line numbers, and reading
+// continues with the next line if the loop is not stopped. Reading stops after
+// the first error returned by the reader. By default, the package-level
+// registry is used, use [WithRegistry] to provide a custom one. The
+// [WithStrict], [WithUnknown] and [WithAllowedTypes] options are supported.
+func ReadNDJSON(r io.Reader, opts ...Option) iter.Seq2[*Value, error] {
+	def := newOptions(opts...)
+	return func(yield func(*Value, error) bool) {
+		br := bufio.NewReader(r)
+		for num := 1; ; num++ {
+			line, err := br.ReadBytes('\n')
+			if err != nil && !errors.Is(err, io.EOF) {
+				yield(nil, fmt.Errorf("line %d: %w", num, err))
+				return
+			}
+			if data := bytes.TrimSpace(line); len(data) > 0 {
+				val := &Value{}
+				if uErr := unmarshal(def, data, val); uErr != nil {
+					uErr = fmt.Errorf("line %d: %w", num, uErr)
+					if !yield(nil, uErr) {
+						return
+					}
+				} else if !yield(val, nil) {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// NDJSONWriter writes values as newline-delimited JSON (NDJSON). It is safe
+// for concurrent use, each value is written with a single call to the
+// underlying writer.
+type NDJSONWriter struct {
+	w   io.Writer  // Underlying writer.
+	def *Options   // Writer options.
+	mx  sync.Mutex // Guards writes.
+}
+
+// NewNDJSONWriter returns a new writer that writes to w. By default, the
+// package-level registry is used, use [WithRegistry] to provide a custom one.
+func NewNDJSONWriter(w io.Writer, opts ...Option) *NDJSONWriter {
+	return &NDJSONWriter{w: w, def: newOptions(opts...)}
+}
+
+// Write writes the JSON representation of the value followed by a newline.
+func (w *NDJSONWriter) Write(val *Value) error {
+	v, err := envelope(w.def.reg, val)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(v) // Always compact, so it fits in one line.
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	w.mx.Lock()
+	defer w.mx.Unlock()
+	if _, err = w.w.Write(data); err != nil {
+		return fmt.Errorf("jsontype: %w", err)
+	}
+	return nil
+}
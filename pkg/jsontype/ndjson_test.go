@@ -0,0 +1,304 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ctx42/convert/pkg/convert"
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
+)
+
+// errReader is a reader returning data followed by an error.
+type errReader struct {
+	data string
+	err  error
+}
+
+func (r *errReader) Read(p []byte) (int, error) {
+	if r.data == "" {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+// errWriter is a writer which always fails.
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) { return 0, errors.New("write") }
+
+// collect returns values and errors from the iterator.
+func collect(t *testing.T, data string, opts ...Option) ([]*Value, []error) {
+	t.Helper()
+	var vals []*Value
+	var errs []error
+	for val, err := range ReadNDJSON(strings.NewReader(data), opts...) {
+		vals = append(vals, val)
+		errs = append(errs, err)
+	}
+	return vals, errs
+}
+
+func Test_ReadNDJSON(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint64", "value": 18446744073709551615}` + "\n" +
+			"\n" +
+			`  {"type": "time.Duration", "value": "1s"}  ` + "\r\n" +
+			`{"type": "nil", "value": null}`
+
+		// --- When ---
+		vals, errs := collect(t, data)
+
+		// --- Then ---
+		assert.Equal(t, []error{nil, nil, nil}, errs)
+		want := []*Value{
+			{typ: "uint64", val: uint64(1<<64 - 1)},
+			{typ: "time.Duration", val: time.Second},
+			{typ: "nil", val: nil},
+		}
+		assert.Equal(t, want, vals)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		// --- When ---
+		vals, errs := collect(t, "")
+
+		// --- Then ---
+		assert.Nil(t, vals)
+		assert.Nil(t, errs)
+	})
+
+	t.Run("custom registry", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+		data := `{"type": "circle", "value": {"r": 1}}` + "\n"
+
+		// --- When ---
+		vals, errs := collect(t, data, WithRegistry(reg))
+
+		// --- Then ---
+		assert.Equal(t, []error{nil}, errs)
+		assert.Equal(t, []*Value{{typ: "circle", val: TCircle{R: 1}}}, vals)
+	})
+
+	t.Run("long line", func(t *testing.T) {
+		// --- Given ---
+		str := strings.Repeat("a", 1<<17)
+		data := `{"type": "string", "value": "` + str + `"}`
+
+		// --- When ---
+		vals, errs := collect(t, data)
+
+		// --- Then ---
+		assert.Equal(t, []error{nil}, errs)
+		assert.Equal(t, []*Value{{typ: "string", val: str}}, vals)
+	})
+
+	t.Run("errors have line numbers", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint8", "value": 1}` + "\n" +
+			`{"type": "uint8", "value": 256}` + "\n" +
+			"\n" +
+			`{!}` + "\n" +
+			`{"type": "uint8", "value": 2}` + "\n"
+
+		// --- When ---
+		vals, errs := collect(t, data)
+
+		// --- Then ---
+		assert.Len(t, 4, errs)
+		assert.NoError(t, errs[0])
+		assert.ErrorIs(t, convert.ErrInvRange, errs[1])
+		assert.ErrorContain(t, "line 2: jsontype: ", errs[1])
+		assert.ErrorContain(t, "line 4: jsontype: invalid character", errs[2])
+		assert.NoError(t, errs[3])
+		want := []*Value{
+			{typ: "uint8", val: uint8(1)},
+			nil,
+			nil,
+			{typ: "uint8", val: uint8(2)},
+		}
+		assert.Equal(t, want, vals)
+	})
+
+	t.Run("stop on value", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint8", "value": 1}` + "\n" +
+			`{"type": "uint8", "value": 2}` + "\n"
+
+		// --- When ---
+		var vals []*Value
+		for val := range ReadNDJSON(strings.NewReader(data)) {
+			vals = append(vals, val)
+			break
+		}
+
+		// --- Then ---
+		assert.Equal(t, []*Value{{typ: "uint8", val: uint8(1)}}, vals)
+	})
+
+	t.Run("stop on error", func(t *testing.T) {
+		// --- Given ---
+		data := `{!}` + "\n" + `{"type": "uint8", "value": 2}` + "\n"
+
+		// --- When ---
+		var errs []error
+		for _, err := range ReadNDJSON(strings.NewReader(data)) {
+			errs = append(errs, err)
+			break
+		}
+
+		// --- Then ---
+		assert.Len(t, 1, errs)
+		assert.ErrorContain(t, "line 1: ", errs[0])
+	})
+
+	t.Run("error - reader", func(t *testing.T) {
+		// --- Given ---
+		rErr := errors.New("read")
+		r := &errReader{data: `{"type": "uint8", "value": 1}` + "\n", err: rErr}
+
+		// --- When ---
+		var vals []*Value
+		var errs []error
+		for val, err := range ReadNDJSON(r) {
+			vals = append(vals, val)
+			errs = append(errs, err)
+		}
+
+		// --- Then ---
+		assert.Len(t, 2, errs)
+		assert.NoError(t, errs[0])
+		assert.ErrorIs(t, rErr, errs[1])
+		assert.ErrorEqual(t, "line 2: read", errs[1])
+		assert.Equal(t, []*Value{{typ: "uint8", val: uint8(1)}, nil}, vals)
+	})
+}
+
+func Test_NewNDJSONWriter(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		// --- Given ---
+		buf := &bytes.Buffer{}
+
+		// --- When ---
+		have := NewNDJSONWriter(buf)
+
+		// --- Then ---
+		assert.Same(t, buf, have.w)
+		assert.Same(t, registry, have.def.reg)
+	})
+
+	t.Run("with options", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+
+		// --- When ---
+		have := NewNDJSONWriter(&bytes.Buffer{}, WithRegistry(reg))
+
+		// --- Then ---
+		assert.Same(t, reg, have.def.reg)
+	})
+}
+
+func Test_NDJSONWriter_Write(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		buf := &bytes.Buffer{}
+		w := NewNDJSONWriter(buf)
+
+		// --- When ---
+		err0 := w.Write(New(uint64(1<<64 - 1)))
+		err1 := w.Write(New(time.Second))
+
+		// --- Then ---
+		assert.NoError(t, err0)
+		assert.NoError(t, err1)
+		want := `{"type":"uint64","value":18446744073709551615}` + "\n" +
+			`{"type":"time.Duration","value":"1s"}` + "\n"
+		assert.Equal(t, want, buf.String())
+	})
+
+	t.Run("custom registry", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+		buf := &bytes.Buffer{}
+		w := NewNDJSONWriter(buf, WithRegistry(reg))
+
+		// --- When ---
+		err := w.Write(&Value{typ: "circle", val: TCircle{R: 1}})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, `{"type":"circle","value":{"r":1}}`+"\n", buf.String())
+	})
+
+	t.Run("unknown value is compacted", func(t *testing.T) {
+		// --- Given ---
+		data := "{\n\"type\": \"money\",\n\"value\": 1\n}"
+		val := must.Value(UnmarshalValue([]byte(data), WithUnknown()))
+		buf := &bytes.Buffer{}
+		w := NewNDJSONWriter(buf)
+
+		// --- When ---
+		err := w.Write(val)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, `{"type":"money","value":1}`+"\n", buf.String())
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		// --- Given ---
+		buf := &bytes.Buffer{}
+		w := NewNDJSONWriter(buf)
+
+		// --- When ---
+		var wg sync.WaitGroup
+		for i := range 100 {
+			wg.Go(func() { must.Nil(w.Write(New(uint64(i)))) })
+		}
+		wg.Wait()
+
+		// --- Then ---
+		var have []uint64
+		for val, err := range ReadNDJSON(buf) {
+			must.Nil(err)
+			have = append(have, val.GoValue().(uint64))
+		}
+		assert.Len(t, 100, have)
+	})
+
+	t.Run("error - invalid value", func(t *testing.T) {
+		// --- Given ---
+		buf := &bytes.Buffer{}
+		w := NewNDJSONWriter(buf)
+
+		// --- When ---
+		err := w.Write(&Value{})
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvValue, err)
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("error - writer", func(t *testing.T) {
+		// --- Given ---
+		w := NewNDJSONWriter(errWriter{})
+
+		// --- When ---
+		err := w.Write(New(uint(1)))
+
+		// --- Then ---
+		assert.ErrorEqual(t, "jsontype: write", err)
+	})
+}
@@ -3,15 +3,125 @@
 
 package jsontype
 
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrTypeNotAllowed is returned when the type name is rejected by the type
+// filter set with [WithAllowedTypes] or [WithTypeFilter].
+var ErrTypeNotAllowed = errors.New("type not allowed")
+
 // Option represents a configuration option.
 type Option func(*Options)
 
 // Options represents configuration options.
 type Options struct {
-	reg *Registry
+	reg     *Registry
+	strict  bool
+	unknown bool
+	lazy    bool
+	rules   []InferRule
+	allow   func(name string) bool // Type filter, nil allows all types.
+
+	strictReg bool      // Registry registration policy.
+	parent    *Registry // Parent of the new registry.
+}
+
+// newOptions returns [Options] with default values and the given options
+// applied. By default, the package-level registry is used.
+func newOptions(opts ...Option) *Options {
+	def := &Options{reg: registry, rules: inferRules}
+	for _, opt := range opts {
+		opt(def)
+	}
+	return def
 }
 
 // WithRegistry creates an [Option] that sets the registry.
 func WithRegistry(reg *Registry) Option {
 	return func(opt *Options) { opt.reg = reg }
 }
+
+// WithStrict creates an [Option] which turns on the strict mode. In the strict
+// mode, decoding rejects value representations with fields other than "type"
+// and "value". Passed to [RegisterStruct], it makes the registered converter
+// reject unknown fields of the struct; the struct strictness is fixed at
+// registration and doesn't depend on the options used for decoding.
+func WithStrict() Option {
+	return func(opt *Options) { opt.strict = true }
+}
+
+// WithUnknown creates an [Option] which turns on decoding of values with type
+// names not known to the registry as [UnknownValue] instances.
+func WithUnknown() Option {
+	return func(opt *Options) { opt.unknown = true }
+}
+
+// WithLazy creates an [Option] which turns on the lazy decoding mode. In the
+// lazy mode, decoded values keep their raw JSON and are converted on the first
+// call to [Value.GoValue] or [Value.Decode].
+func WithLazy() Option {
+	return func(opt *Options) { opt.lazy = true }
+}
+
+// WithInferRules creates an [Option] which sets the rules used by [Infer].
+// Rules are tried in the given order. Calling it without rules turns the
+// inference off.
+func WithInferRules(rules ...InferRule) Option {
+	return func(opt *Options) { opt.rules = rules }
+}
+
+// WithAllowedTypes creates an [Option] which limits the type names accepted
+// when decoding or creating values to the given ones. Values of other types
+// are rejected with [ErrTypeNotAllowed], even when the registry supports them.
+// Composite type names, like "[]int", must be listed explicitly.
+func WithAllowedTypes(names ...string) Option {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return WithTypeFilter(func(name string) bool { return set[name] })
+}
+
+// WithTypeFilter creates an [Option] which limits the type names accepted
+// when decoding or creating values to the ones for which the filter returns
+// true. Values of other types are rejected with [ErrTypeNotAllowed]. When
+// combined with other filters, type names must be accepted by all of them.
+//
+// The filter is applied to type names of the values, not to type names used
+// inside converters of the allowed types, for example, by struct fields.
+func WithTypeFilter(fn func(name string) bool) Option {
+	return func(opt *Options) {
+		if prev := opt.allow; prev != nil {
+			opt.allow = func(name string) bool { return prev(name) && fn(name) }
+			return
+		}
+		opt.allow = fn
+	}
+}
+
+// WithStrictRegister creates an [Option] which makes [NewRegistry] and
+// [DefaultRegistry] return a registry where registering an already registered
+// type name panics with [ConflictError] instead of replacing the converter.
+func WithStrictRegister() Option {
+	return func(opt *Options) { opt.strictReg = true }
+}
+
+// WithParent creates an [Option] which makes [NewRegistry] and
+// [DefaultRegistry] return a child of the given registry. Type names not
+// registered in the child are looked up in the parent, type names registered
+// in the child shadow the ones in the parent. Use [Registry.Block] to hide
+// type names of the parent.
+func WithParent(parent *Registry) Option {
+	return func(opt *Options) { opt.parent = parent }
+}
+
+// allowed returns an error wrapping [ErrTypeNotAllowed] when the type name is
+// rejected by the type filter.
+func (def *Options) allowed(typ string) error {
+	if def.allow == nil || def.allow(typ) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrTypeNotAllowed, typ)
+}
@@ -9,6 +9,30 @@ import (
 	"github.com/ctx42/testing/pkg/assert"
 )
 
+func Test_newOptions(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		// --- When ---
+		have := newOptions()
+
+		// --- Then ---
+		assert.Same(t, registry, have.reg)
+		assert.False(t, have.strict)
+		assert.Len(t, 2, have.rules)
+		assert.Nil(t, have.allow)
+	})
+
+	t.Run("with options", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+
+		// --- When ---
+		have := newOptions(WithRegistry(reg))
+
+		// --- Then ---
+		assert.Same(t, reg, have.reg)
+	})
+}
+
 func Test_WithRegistry(t *testing.T) {
 	// --- Given ---
 	reg := NewRegistry()
@@ -20,3 +44,163 @@ func Test_WithRegistry(t *testing.T) {
 	// --- Then ---
 	assert.Same(t, reg, ops.reg)
 }
+
+func Test_WithStrict(t *testing.T) {
+	// --- Given ---
+	ops := &Options{}
+
+	// --- When ---
+	WithStrict()(ops)
+
+	// --- Then ---
+	assert.True(t, ops.strict)
+}
+
+func Test_WithUnknown(t *testing.T) {
+	// --- Given ---
+	ops := &Options{}
+
+	// --- When ---
+	WithUnknown()(ops)
+
+	// --- Then ---
+	assert.True(t, ops.unknown)
+}
+
+func Test_WithLazy(t *testing.T) {
+	// --- Given ---
+	ops := &Options{}
+
+	// --- When ---
+	WithLazy()(ops)
+
+	// --- Then ---
+	assert.True(t, ops.lazy)
+}
+
+func Test_WithInferRules(t *testing.T) {
+	t.Run("rules", func(t *testing.T) {
+		// --- Given ---
+		ops := &Options{}
+
+		// --- When ---
+		WithInferRules(InferTime)(ops)
+
+		// --- Then ---
+		assert.Len(t, 1, ops.rules)
+	})
+
+	t.Run("no rules", func(t *testing.T) {
+		// --- Given ---
+		ops := &Options{rules: inferRules}
+
+		// --- When ---
+		WithInferRules()(ops)
+
+		// --- Then ---
+		assert.Len(t, 0, ops.rules)
+	})
+}
+
+func Test_WithAllowedTypes(t *testing.T) {
+	// --- Given ---
+	ops := &Options{}
+
+	// --- When ---
+	WithAllowedTypes(Int, "[]int")(ops)
+
+	// --- Then ---
+	assert.True(t, ops.allow(Int))
+	assert.True(t, ops.allow("[]int"))
+	assert.False(t, ops.allow(Uint))
+	assert.False(t, ops.allow("[]uint"))
+}
+
+func Test_WithTypeFilter(t *testing.T) {
+	t.Run("single", func(t *testing.T) {
+		// --- Given ---
+		ops := &Options{}
+		fn := func(name string) bool { return name == Int }
+
+		// --- When ---
+		WithTypeFilter(fn)(ops)
+
+		// --- Then ---
+		assert.True(t, ops.allow(Int))
+		assert.False(t, ops.allow(Uint))
+	})
+
+	t.Run("combined", func(t *testing.T) {
+		// --- Given ---
+		ops := &Options{}
+		fn := func(name string) bool { return name != Uint }
+
+		// --- When ---
+		WithAllowedTypes(Int, Uint)(ops)
+		WithTypeFilter(fn)(ops)
+
+		// --- Then ---
+		assert.True(t, ops.allow(Int))
+		assert.False(t, ops.allow(Uint))
+		assert.False(t, ops.allow(Int8))
+	})
+}
+
+func Test_Options_allowed(t *testing.T) {
+	t.Run("no filter", func(t *testing.T) {
+		// --- Given ---
+		ops := &Options{}
+
+		// --- When ---
+		err := ops.allowed("abc")
+
+		// --- Then ---
+		assert.NoError(t, err)
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		// --- Given ---
+		ops := newOptions(WithAllowedTypes(Int))
+
+		// --- When ---
+		err := ops.allowed(Int)
+
+		// --- Then ---
+		assert.NoError(t, err)
+	})
+
+	t.Run("not allowed", func(t *testing.T) {
+		// --- Given ---
+		ops := newOptions(WithAllowedTypes(Int))
+
+		// --- When ---
+		err := ops.allowed(Uint)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrTypeNotAllowed, err)
+		assert.ErrorEqual(t, "type not allowed: uint", err)
+	})
+}
+
+func Test_WithStrictRegister(t *testing.T) {
+	// --- Given ---
+	ops := &Options{}
+
+	// --- When ---
+	WithStrictRegister()(ops)
+
+	// --- Then ---
+	assert.True(t, ops.strictReg)
+}
+
+func Test_WithParent(t *testing.T) {
+	// --- Given ---
+	reg := NewRegistry()
+	ops := &Options{}
+
+	// --- When ---
+	WithParent(reg)(ops)
+
+	// --- Then ---
+	assert.Same(t, reg, ops.parent)
+}
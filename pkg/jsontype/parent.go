@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"maps"
+	"reflect"
+
+	"github.com/ctx42/convert/pkg/convert"
+)
+
+// Block blocks the type names inherited from the parent registry, so they are
+// not supported by the registry unless registered in it. Blocking type names
+// which are not registered in the parent is allowed, they will stay blocked
+// when the parent registers them later. Panics with [ErrFrozen] when the
+// registry is frozen.
+func (reg *Registry) Block(names ...string) {
+	err := reg.update(func(snap *snapshot) error {
+		snap.blk = maps.Clone(snap.blk)
+		for _, name := range names {
+			snap.blk[name] = true
+		}
+		return nil
+	})
+	reg.check(err)
+}
+
+// find returns the converter and the Go type registered for the type name in
+// the registry or, when it's not registered there, in its ancestors. Returns
+// true when the type name is registered or blocked.
+func (reg *Registry) find(name string) (convert.AnyToAny, reflect.Type, bool) {
+	for r := reg; r != nil; r = r.parent {
+		snap := r.snap.Load()
+		if cnv := snap.reg[name]; cnv != nil {
+			return cnv, snap.typ[name], true
+		}
+		if snap.blk[name] {
+			return nil, nil, true
+		}
+	}
+	return nil, nil, false
+}
+
+// findEncoder returns the encoder registered for the type name in the
+// registry or its ancestors. Encoders registered in ancestors are shadowed by
+// converters registered for the same type name, so they are never used with
+// converters they don't match. Returns true when the encoder was found or
+// the type name is blocked.
+func (reg *Registry) findEncoder(name string) (convert.AnyToAny, bool) {
+	for r := reg; r != nil; r = r.parent {
+		snap := r.snap.Load()
+		if enc := snap.enc[name]; enc != nil {
+			return enc, true
+		}
+		if snap.reg[name] != nil {
+			return nil, false
+		}
+		if snap.blk[name] {
+			return nil, true
+		}
+	}
+	return nil, false
+}
+
+// appender returns the fast path encoder for the given type name, or nil.
+// Like encoders, they are shadowed by converters and encoders registered for
+// the same type name.
+func (reg *Registry) appender(name string) appender {
+	for r := reg; r != nil; r = r.parent {
+		snap := r.snap.Load()
+		if app := snap.app[name]; app != nil {
+			return app
+		}
+		if snap.reg[name] != nil || snap.enc[name] != nil || snap.blk[name] {
+			return nil
+		}
+	}
+	return nil
+}
+
+// cache returns the lookup cache when the registry and all its ancestors are
+// frozen, otherwise it returns nil.
+func (reg *Registry) cache() *lookupCache {
+	cache := reg.snap.Load().cache
+	for r := reg.parent; r != nil && cache != nil; r = r.parent {
+		if r.snap.Load().cache == nil {
+			return nil
+		}
+	}
+	return cache
+}
+
+// converters returns all converters supported by the registry, including
+// the ones inherited from its ancestors. The returned map must not be
+// modified.
+func (reg *Registry) converters() map[string]convert.AnyToAny {
+	return inherited(reg, func(snap *snapshot) map[string]convert.AnyToAny {
+		return snap.reg
+	})
+}
+
+// types returns Go types of all converters supported by the registry,
+// including the ones inherited from its ancestors. The returned map must not
+// be modified.
+func (reg *Registry) types() map[string]reflect.Type {
+	return inherited(reg, func(snap *snapshot) map[string]reflect.Type {
+		return snap.typ
+	})
+}
+
+// inherited returns the map returned by get for the registry merged with the
+// maps of its ancestors. Type names registered or blocked in the registry
+// shadow the ones in the ancestors. The returned map must not be modified.
+func inherited[V any](
+	reg *Registry,
+	get func(snap *snapshot) map[string]V,
+) map[string]V {
+
+	snap := reg.snap.Load()
+	if reg.parent == nil {
+		return get(snap)
+	}
+	m := maps.Clone(inherited(reg.parent, get))
+	for name := range snap.blk {
+		delete(m, name)
+	}
+	for name := range snap.reg {
+		delete(m, name)
+	}
+	maps.Copy(m, get(snap))
+	return m
+}
@@ -0,0 +1,341 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ctx42/convert/pkg/convert"
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
+)
+
+func Test_Registry_parent(t *testing.T) {
+	t.Run("fallback to parent", func(t *testing.T) {
+		// --- Given ---
+		base := DefaultRegistry()
+
+		// --- When ---
+		reg := NewRegistry(WithParent(base))
+
+		// --- Then ---
+		assert.Same(t, base.Converter(Int), reg.Converter(Int))
+		assert.Same(t, base.Encoder(Time), reg.Encoder(Time))
+		assert.Equal(t, reflect.TypeFor[int](), reg.GoType(Int))
+		assert.Equal(t, reflect.TypeFor[[]int](), reg.GoType("[]int"))
+		assert.NotNil(t, reg.appender(Duration))
+		assert.True(t, reg.Has(Int))
+		assert.Equal(t, 19, reg.Len())
+		assert.Len(t, 0, reg.snap.Load().reg)
+	})
+
+	t.Run("grandparent", func(t *testing.T) {
+		// --- Given ---
+		base := DefaultRegistry()
+		mid := NewRegistry(WithParent(base))
+
+		// --- When ---
+		reg := NewRegistry(WithParent(mid))
+
+		// --- Then ---
+		assert.Same(t, base.Converter(Int), reg.Converter(Int))
+		assert.Equal(t, base.Names(), reg.Names())
+	})
+
+	t.Run("parent registrations are visible", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		base := NewRegistry()
+		reg := NewRegistry(WithParent(base))
+
+		// --- When ---
+		base.Register("abc", cnv)
+
+		// --- Then ---
+		assert.Same(t, cnv, reg.Converter("abc"))
+	})
+
+	t.Run("child registrations are not visible in parent", func(t *testing.T) {
+		// --- Given ---
+		base := DefaultRegistry()
+		reg := NewRegistry(WithParent(base))
+
+		// --- When ---
+		must.Nil(RegisterStruct[TCircle]("circle", WithRegistry(reg)))
+
+		// --- Then ---
+		assert.NotNil(t, reg.Converter("circle"))
+		assert.NotNil(t, reg.Converter("[]circle"))
+		assert.Nil(t, base.Converter("circle"))
+		assert.Nil(t, base.Converter("[]circle"))
+		assert.Equal(t, 20, reg.Len())
+		assert.Equal(t, 19, base.Len())
+	})
+
+	t.Run("child converter shadows parent", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		base := DefaultRegistry()
+		reg := NewRegistry(WithParent(base))
+
+		// --- When ---
+		old := reg.Register(Duration, cnv)
+
+		// --- Then ---
+		assert.Nil(t, old)
+		assert.Same(t, cnv, reg.Converter(Duration))
+		assert.Nil(t, reg.GoType(Duration))
+		assert.Nil(t, reg.Encoder(Duration))
+		assert.Nil(t, reg.appender(Duration))
+		assert.NotNil(t, base.Encoder(Duration))
+		assert.Equal(t, 19, reg.Len())
+	})
+
+	t.Run("child converter is used by composites", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return 42, nil }
+		base := DefaultRegistry()
+		reg := NewRegistry(WithParent(base))
+		reg.RegisterType(Int, reflect.TypeFor[int](), cnv)
+
+		// --- When ---
+		have, err := reg.Converter("[]int")([]any{1.0})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []int{42}, have)
+	})
+
+	t.Run("child encoder shadows parent", func(t *testing.T) {
+		// --- Given ---
+		base := DefaultRegistry()
+		reg := NewRegistry(WithParent(base))
+		enc := func(v time.Duration) (int64, error) { return int64(v), nil }
+
+		// --- When ---
+		reg.RegisterEncoder(Duration, convert.ToAnyAny(enc))
+
+		// --- Then ---
+		assert.Same(t, base.Converter(Duration), reg.Converter(Duration))
+		have := must.Value(Marshal(reg, New(time.Second)))
+		want := `{"type":"time.Duration","value":1000000000}`
+		assert.Equal(t, want, string(have))
+		have = must.Value(Marshal(base, New(time.Second)))
+		assert.Equal(t, `{"type":"time.Duration","value":"1s"}`, string(have))
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		// --- Given ---
+		base := DefaultRegistry()
+		must.Nil(RegisterStruct[TCircle]("circle", WithRegistry(base)))
+		reg := NewRegistry(WithParent(base))
+		must.Nil(RegisterStruct[TSquare]("square", WithRegistry(reg)))
+		m := Map{
+			"circle": TCircle{R: 1.5},
+			"square": TSquare{A: 2},
+			"time":   time.Second,
+		}
+
+		// --- When ---
+		data := must.Value(MarshalMap(reg, m))
+
+		// --- Then ---
+		var have Map
+		assert.NoError(t, UnmarshalMap(reg, data, &have))
+		assert.Equal(t, m, have)
+	})
+
+	t.Run("unregister in child", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		base := DefaultRegistry()
+		reg := NewRegistry(WithParent(base))
+		reg.Register(Int, cnv)
+
+		// --- When ---
+		have := reg.Unregister(Int)
+
+		// --- Then ---
+		assert.Same(t, cnv, have)
+		assert.Same(t, base.Converter(Int), reg.Converter(Int))
+		assert.Nil(t, reg.Unregister(Int))
+		assert.True(t, base.Has(Int))
+	})
+
+	t.Run("strict register shadows parent", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		base := DefaultRegistry()
+		reg := NewRegistry(WithParent(base), WithStrictRegister())
+
+		// --- When ---
+		err := reg.RegisterStrict(Int, cnv)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Same(t, cnv, reg.Converter(Int))
+		assert.ErrorIs(t, ErrConflict, reg.RegisterStrict(Int, cnv))
+	})
+
+	t.Run("clone keeps parent", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		base := NewRegistry()
+		reg := NewRegistry(WithParent(base))
+
+		// --- When ---
+		have := reg.Clone()
+
+		// --- Then ---
+		base.Register(Int, cnv)
+		assert.Same(t, cnv, have.Converter(Int))
+	})
+
+	t.Run("lookup cache requires frozen ancestors", func(t *testing.T) {
+		// --- Given ---
+		base := DefaultRegistry()
+		reg := NewRegistry(WithParent(base))
+
+		// --- When ---
+		reg.Freeze()
+
+		// --- Then ---
+		assert.True(t, reg.IsFrozen())
+		assert.Nil(t, reg.cache())
+		base.Freeze()
+		assert.NotNil(t, reg.cache())
+		assert.Same(t, reg.Converter("[]int"), reg.Converter("[]int"))
+	})
+
+	t.Run("concurrent parent registrations", func(t *testing.T) {
+		// --- Given ---
+		base := DefaultRegistry()
+		reg := NewRegistry(WithParent(base))
+		enc := func(v any) (any, error) { return v, nil }
+
+		// --- When ---
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Go(func() { base.RegisterType("abc", nil, enc) })
+			wg.Go(func() { reg.Block("xyz") })
+			wg.Go(func() {
+				reg.Converter("[]int")
+				reg.Encoder("abc")
+				reg.Names()
+			})
+		}
+		wg.Wait()
+
+		// --- Then ---
+		assert.True(t, reg.Has("abc"))
+	})
+}
+
+func Test_Registry_Block(t *testing.T) {
+	t.Run("block inherited", func(t *testing.T) {
+		// --- Given ---
+		base := DefaultRegistry()
+		reg := NewRegistry(WithParent(base))
+
+		// --- When ---
+		reg.Block(Time, Duration)
+
+		// --- Then ---
+		assert.Nil(t, reg.Converter(Time))
+		assert.Nil(t, reg.GoType(Time))
+		assert.Nil(t, reg.Encoder(Time))
+		assert.Nil(t, reg.Converter("[]time.Duration"))
+		assert.Nil(t, reg.appender(Duration))
+		assert.False(t, reg.Has(Time))
+		assert.Equal(t, 17, reg.Len())
+		assert.False(t, slices.Contains(reg.Names(), Time))
+		assert.True(t, base.Has(Time))
+	})
+
+	t.Run("block composite", func(t *testing.T) {
+		// --- Given ---
+		base := DefaultRegistry()
+		reg := NewRegistry(WithParent(base))
+
+		// --- When ---
+		reg.Block("[]int")
+
+		// --- Then ---
+		assert.Nil(t, reg.Converter("[]int"))
+		assert.NotNil(t, reg.Converter("[]uint"))
+	})
+
+	t.Run("blocked in grandchild", func(t *testing.T) {
+		// --- Given ---
+		base := DefaultRegistry()
+		mid := NewRegistry(WithParent(base))
+		mid.Block(Time)
+
+		// --- When ---
+		reg := NewRegistry(WithParent(mid))
+
+		// --- Then ---
+		assert.Nil(t, reg.Converter(Time))
+		assert.Equal(t, 18, reg.Len())
+	})
+
+	t.Run("registered after block", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry(WithParent(DefaultRegistry()))
+		reg.Block(Int)
+
+		// --- When ---
+		reg.Register(Int, cnv)
+
+		// --- Then ---
+		assert.Same(t, cnv, reg.Converter(Int))
+		assert.True(t, reg.Has(Int))
+		assert.Equal(t, 19, reg.Len())
+	})
+
+	t.Run("registered in parent after block", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		base := NewRegistry()
+		reg := NewRegistry(WithParent(base))
+		reg.Block("abc")
+
+		// --- When ---
+		base.Register("abc", cnv)
+
+		// --- Then ---
+		assert.Nil(t, reg.Converter("abc"))
+	})
+
+	t.Run("encoding blocked type", func(t *testing.T) {
+		// --- Given ---
+		base := DefaultRegistry()
+		must.Nil(RegisterStruct[TCircle]("circle", WithRegistry(base)))
+		reg := NewRegistry(WithParent(base))
+		reg.Block("circle")
+
+		// --- When ---
+		_, err := MarshalMap(reg, Map{"c": TCircle{R: 1}})
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+	})
+
+	t.Run("frozen", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry(WithParent(DefaultRegistry()))
+		reg.Freeze()
+
+		// --- Then ---
+		assert.PanicContain(t, "registry is frozen", func() {
+			reg.Block(Int)
+		})
+		assert.True(t, reg.Has(Int))
+	})
+}
@@ -4,40 +4,450 @@
 package jsontype
 
 import (
+	"fmt"
+	"iter"
+	"maps"
+	"reflect"
+	"slices"
 	"sync"
+	"sync/atomic"
 
 	"github.com/ctx42/convert/pkg/convert"
 )
 
 // Registry maps type names to their converters.
+//
+// Lookups are lock-free: they read an immutable snapshot of the registry
+// through an atomic pointer. Registrations copy the snapshot, modify the copy
+// and swap it in, so they are more expensive than lookups and should be done
+// mostly during the program initialization.
+//
+// A registry created with [WithParent] falls back to its parent for type
+// names it doesn't have registered.
 type Registry struct {
+	snap   atomic.Pointer[snapshot]
+	mx     sync.Mutex  // Serializes registrations.
+	strict atomic.Bool // Conflicting registrations panic.
+	parent *Registry   // Registry used for not registered type names.
+}
+
+// snapshot is an immutable state of the [Registry]. It must not be modified
+// after it is stored in the registry.
+type snapshot struct {
 	reg map[string]convert.AnyToAny
-	mx  sync.RWMutex
+	typ map[string]reflect.Type     // Go types returned by the converters.
+	enc map[string]convert.AnyToAny // Encoders.
+	app map[string]appender         // Fast path encoders of built-in types.
+	pos map[string]string           // Call sites of the registrations.
+	blk map[string]bool             // Type names blocked in the parent.
+	num map[string]bool             // Converters accepting json.Number.
+	cfl map[string]*ConflictError   // First replacements (not strict).
+
+	cache *lookupCache // Composite type lookups, not nil when frozen.
 }
 
-// NewRegistry returns a new instance of [Registry].
-func NewRegistry() *Registry {
-	return &Registry{reg: make(map[string]convert.AnyToAny, 20)}
+// NewRegistry returns a new instance of [Registry]. Use [WithStrictRegister]
+// to make registrations of already registered type names panic, and
+// [WithParent] to create a child of another registry.
+func NewRegistry(opts ...Option) *Registry {
+	def := newOptions(opts...)
+	reg := &Registry{parent: def.parent}
+	reg.strict.Store(def.strictReg)
+	reg.snap.Store(&snapshot{
+		reg: make(map[string]convert.AnyToAny),
+		typ: make(map[string]reflect.Type),
+		enc: make(map[string]convert.AnyToAny),
+		app: make(map[string]appender),
+		pos: make(map[string]string),
+		blk: make(map[string]bool),
+		num: make(map[string]bool),
+	})
+	return reg
+}
+
+// update calls fn with a shallow copy of the current snapshot and stores it
+// as the new one. The fn must clone the maps it modifies. When fn returns an
+// error, the snapshot is not changed. Returns [ErrFrozen] when the registry
+// is frozen.
+func (reg *Registry) update(fn func(snap *snapshot) error) error {
+	reg.mx.Lock()
+	defer reg.mx.Unlock()
+	snap := *reg.snap.Load()
+	if snap.cache != nil {
+		return ErrFrozen
+	}
+	if err := fn(&snap); err != nil {
+		return err
+	}
+	reg.snap.Store(&snap)
+	return nil
 }
 
 // Register registers a converter for the given type name. When the converter
 // for it already exists, it will return it, nil otherwise.
+//
+// The Go type returned by the converter is not known, so the type name cannot
+// be used as an element of composite types. Use [Registry.RegisterType] for
+// that.
 func (reg *Registry) Register(name string, cnv convert.AnyToAny) convert.AnyToAny {
+	return reg.RegisterType(name, nil, cnv)
+}
+
+// RegisterType works like [Registry.Register] but also records the Go type
+// the converter returns. Knowing the Go type allows using the type name as an
+// element of composite types like slices, arrays, and maps.
+//
+// For registries created with [WithStrictRegister], registering an already
+// registered type name panics with [ConflictError].
+func (reg *Registry) RegisterType(
+	name string,
+	typ reflect.Type,
+	cnv convert.AnyToAny,
+) convert.AnyToAny {
+
 	if cnv == nil {
 		return nil
 	}
-	reg.mx.Lock()
-	defer reg.mx.Unlock()
+	old, err := reg.register(name, typ, cnv, false, false)
+	reg.check(err)
+	return old
+}
+
+// RegisterNumber works like [Registry.RegisterType] but the converter gets
+// numbers as [json.Number] instead of float64, so it can decode them without
+// loss of precision. Numbers nested in arrays and objects are passed as
+// [json.Number] too.
+func (reg *Registry) RegisterNumber(
+	name string,
+	typ reflect.Type,
+	cnv convert.AnyToAny,
+) convert.AnyToAny {
 
-	old := reg.reg[name]
-	reg.reg[name] = cnv
+	if cnv == nil {
+		return nil
+	}
+	old, err := reg.register(name, typ, cnv, true, false)
+	reg.check(err)
 	return old
 }
 
+// RegisterStrict works like [Registry.Register] but it doesn't replace
+// already registered converters. Returns [ConflictError] with call sites of
+// both registrations when the type name is already registered, and
+// [ErrFrozen] when the registry is frozen. Type names registered only in the
+// parent registry are not conflicts, they are shadowed.
+func (reg *Registry) RegisterStrict(name string, cnv convert.AnyToAny) error {
+	if cnv == nil {
+		return nil
+	}
+	_, err := reg.register(name, nil, cnv, false, true)
+	return err
+}
+
+// MustRegister works like [Registry.RegisterStrict] but panics on error.
+func (reg *Registry) MustRegister(name string, cnv convert.AnyToAny) {
+	if err := reg.RegisterStrict(name, cnv); err != nil {
+		panic(err)
+	}
+}
+
+// register registers the converter and its Go type. The num marks converters
+// accepting [json.Number] values. In the strict mode (when strict is true or
+// the registry is strict), it returns [ConflictError] when the type name is
+// already registered. Otherwise, the conflict is recorded, see
+// [Registry.SetStrictRegister].
+func (reg *Registry) register(
+	name string,
+	typ reflect.Type,
+	cnv convert.AnyToAny,
+	num bool,
+	strict bool,
+) (convert.AnyToAny, error) {
+
+	pos := callSite()
+	var old convert.AnyToAny
+	err := reg.update(func(snap *snapshot) error {
+		old = snap.reg[name]
+		if old != nil {
+			cfl := &ConflictError{
+				Name:     name,
+				Previous: snap.pos[name],
+				Current:  pos,
+			}
+			if strict || reg.strict.Load() {
+				return cfl
+			}
+			if snap.cfl[name] == nil {
+				snap.cfl = maps.Clone(snap.cfl)
+				if snap.cfl == nil {
+					snap.cfl = make(map[string]*ConflictError)
+				}
+				snap.cfl[name] = cfl
+			}
+		}
+		snap.reg = maps.Clone(snap.reg)
+		snap.reg[name] = cnv
+		snap.typ = maps.Clone(snap.typ)
+		if typ == nil {
+			delete(snap.typ, name)
+		} else {
+			snap.typ[name] = typ
+		}
+		snap.pos = maps.Clone(snap.pos)
+		snap.pos[name] = pos
+		if num {
+			snap.num = maps.Clone(snap.num)
+			snap.num[name] = true
+		} else {
+			snap.num = deleteKey(snap.num, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return old, nil
+}
+
+// RegisterEncoder registers an encoder for the given type name. When the
+// encoder for it already exists, it will return it, nil otherwise.
+//
+// The encoder converts the Go value to the value passed to [json.Marshal]. It
+// should be the inverse of the converter registered for the same type name,
+// so the encoded values can be decoded back. Values of types without an
+// encoder are passed to [json.Marshal] as they are.
+func (reg *Registry) RegisterEncoder(
+	name string,
+	enc convert.AnyToAny,
+) convert.AnyToAny {
+
+	if enc == nil {
+		return nil
+	}
+	var old convert.AnyToAny
+	err := reg.update(func(snap *snapshot) error {
+		old = snap.enc[name]
+		snap.enc = maps.Clone(snap.enc)
+		snap.enc[name] = enc
+		if _, ok := snap.app[name]; ok {
+			// The fast path no longer matches the encoder.
+			snap.app = maps.Clone(snap.app)
+			delete(snap.app, name)
+		}
+		return nil
+	})
+	reg.check(err)
+	return old
+}
+
+// Unregister removes the converter, the Go type and the encoder registered
+// for the given type name. Returns the removed converter, or nil when the
+// type name was not registered. It doesn't affect the parent registry, use
+// [Registry.Block] to hide type names registered in the parent.
+func (reg *Registry) Unregister(name string) convert.AnyToAny {
+	var old convert.AnyToAny
+	err := reg.update(func(snap *snapshot) error {
+		old = snap.reg[name]
+		snap.reg = deleteKey(snap.reg, name)
+		snap.typ = deleteKey(snap.typ, name)
+		snap.enc = deleteKey(snap.enc, name)
+		snap.app = deleteKey(snap.app, name)
+		snap.pos = deleteKey(snap.pos, name)
+		snap.num = deleteKey(snap.num, name)
+		return nil
+	})
+	reg.check(err)
+	return old
+}
+
+// deleteKey returns the map without the key. The map is cloned only when it
+// contains the key.
+func deleteKey[V any](m map[string]V, key string) map[string]V {
+	if _, ok := m[key]; !ok {
+		return m
+	}
+	m = maps.Clone(m)
+	delete(m, key)
+	return m
+}
+
+// Has returns true if a converter is registered for the given type name,
+// including type names inherited from the parent registry. Composite type
+// names are not registered, use [Registry.Converter] to check if they are
+// supported.
+func (reg *Registry) Has(name string) bool {
+	cnv, _, _ := reg.find(name)
+	return cnv != nil
+}
+
+// Len returns the number of registered converters, including the ones
+// inherited from the parent registry.
+func (reg *Registry) Len() int { return len(reg.converters()) }
+
+// Names returns sorted names of types with registered converters, including
+// type names inherited from the parent registry.
+func (reg *Registry) Names() []string {
+	return slices.Sorted(maps.Keys(reg.converters()))
+}
+
+// All returns an iterator over registered type names and their converters in
+// the lexical order of names, including the ones inherited from the parent
+// registry. It iterates over the registry state from the moment of the call,
+// registrations made during the iteration are not visible.
+func (reg *Registry) All() iter.Seq2[string, convert.AnyToAny] {
+	cnvs := reg.converters()
+	return func(yield func(string, convert.AnyToAny) bool) {
+		for _, name := range slices.Sorted(maps.Keys(cnvs)) {
+			if !yield(name, cnvs[name]) {
+				return
+			}
+		}
+	}
+}
+
+// Clone returns a copy of the registry with all its converters, Go types and
+// encoders. Registrations in the copy don't affect the original and vice
+// versa. The copy has the same registration policy and parent as the
+// original, but it's never frozen.
+func (reg *Registry) Clone() *Registry {
+	snap := *reg.snap.Load() // Maps in snapshots are immutable.
+	snap.cache = nil
+	cpy := &Registry{parent: reg.parent}
+	cpy.strict.Store(reg.strict.Load())
+	cpy.snap.Store(&snap)
+	return cpy
+}
+
 // Converter returns a converter for the given type name. When the converter
 // for it is not registered, it returns nil.
+//
+// Besides registered type names, it returns converters for composite types
+// like "[]uint64", "[2]int", "map[string]time.Duration" or "*int" built from
+// the registered element converters.
 func (reg *Registry) Converter(typ string) convert.AnyToAny {
-	reg.mx.RLock()
-	defer reg.mx.RUnlock()
-	return reg.reg[typ]
+	cnv, _ := reg.lookup(typ)
+	return cnv
+}
+
+// GoType returns the Go type the converter for the given type name returns.
+// Returns nil when the type name is not registered or its Go type is unknown.
+func (reg *Registry) GoType(typ string) reflect.Type {
+	_, rt := reg.lookup(typ)
+	return rt
+}
+
+// lookup returns the converter and the Go type for the given type name. It
+// builds converters for composite types. Returns nil converter when the type
+// is not supported.
+func (reg *Registry) lookup(typ string) (convert.AnyToAny, reflect.Type) {
+	cnv, rt, found := reg.find(typ)
+	if found {
+		return cnv, rt
+	}
+	if cache := reg.cache(); cache != nil {
+		return cache.converter(reg, typ)
+	}
+	return compositeConverter(reg, typ)
+}
+
+// decoder returns the converter for the type name prepared for values decoded
+// by [decodeNumber]. Converters not registered with [Registry.RegisterNumber]
+// get numbers as float64, the same way [json.Unmarshal] decodes them. Returns
+// nil when the type name is not supported.
+func (reg *Registry) decoder(typ string) convert.AnyToAny {
+	return reg.decoding(typ, reg.Converter(typ))
+}
+
+// decoding returns the converter registered for the type name prepared for
+// values decoded by [decodeNumber]. See [Registry.decoder].
+func (reg *Registry) decoding(
+	typ string,
+	cnv convert.AnyToAny,
+) convert.AnyToAny {
+
+	if cnv == nil || reg.numbers(typ) {
+		return cnv
+	}
+	return floatNumbers(cnv)
+}
+
+// numbers returns true if the converter for the type name accepts
+// [json.Number] values. Converters built for composite type names do, they
+// pass numbers to element converters the way the elements expect them.
+func (reg *Registry) numbers(typ string) bool {
+	for r := reg; r != nil; r = r.parent {
+		snap := r.snap.Load()
+		if snap.reg[typ] != nil {
+			return snap.num[typ]
+		}
+		if snap.blk[typ] {
+			return false
+		}
+	}
+	return true
+}
+
+// Encoder returns an encoder for the given type name. When the encoder for it
+// is not registered, it returns nil.
+//
+// For composite type names, it returns an encoder built from the registered
+// element encoders. It returns nil when none of the elements has an encoder.
+func (reg *Registry) Encoder(typ string) convert.AnyToAny {
+	enc, found := reg.findEncoder(typ)
+	if found {
+		return enc
+	}
+	if cache := reg.cache(); cache != nil {
+		return cache.encoder(reg, typ)
+	}
+	return compositeEncoder(reg, typ)
+}
+
+// typeName returns the type name for the Go type. It prefers the name returned
+// by [reflect.Type.String] when it is registered for the same Go type. For
+// slices, arrays, maps and pointers it builds the name from element type
+// names, when the registry has a converter for it (for example, maps with
+// bool keys are not supported). As a last resort, it returns the first (in
+// lexical order) type name registered for the Go type. Returns an empty
+// string when the Go type is not known.
+func (reg *Registry) typeName(rt reflect.Type) string {
+	if name := rt.String(); reg.GoType(name) == rt {
+		return name
+	}
+
+	var name string
+	switch rt.Kind() {
+	case reflect.Slice:
+		if elem := reg.typeName(rt.Elem()); elem != "" {
+			name = "[]" + elem
+		}
+	case reflect.Array:
+		if elem := reg.typeName(rt.Elem()); elem != "" {
+			name = fmt.Sprintf("[%d]%s", rt.Len(), elem)
+		}
+	case reflect.Map:
+		key, elem := reg.typeName(rt.Key()), reg.typeName(rt.Elem())
+		if key != "" && elem != "" {
+			name = "map[" + key + "]" + elem
+		}
+	case reflect.Pointer:
+		if elem := reg.typeName(rt.Elem()); elem != "" {
+			name = "*" + elem
+		}
+	default:
+	}
+	if name != "" && reg.Converter(name) != nil {
+		return name
+	}
+
+	var names []string
+	for name, typ := range reg.types() {
+		if typ == rt {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return slices.Min(names)
 }
@@ -4,18 +4,55 @@
 package jsontype
 
 import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/ctx42/convert/pkg/convert"
 	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
 )
 
 func Test_NewRegistry(t *testing.T) {
-	// --- When ---
-	have := NewRegistry()
+	t.Run("default", func(t *testing.T) {
+		// --- When ---
+		have := NewRegistry()
 
-	// --- Then ---
-	assert.Len(t, 0, have.reg)
-	assert.NotNil(t, have.reg)
+		// --- Then ---
+		assert.Len(t, 0, have.snap.Load().reg)
+		assert.NotNil(t, have.snap.Load().reg)
+		assert.Len(t, 0, have.snap.Load().typ)
+		assert.NotNil(t, have.snap.Load().typ)
+		assert.Len(t, 0, have.snap.Load().enc)
+		assert.NotNil(t, have.snap.Load().enc)
+		assert.Len(t, 0, have.snap.Load().pos)
+		assert.NotNil(t, have.snap.Load().pos)
+		assert.False(t, have.strict.Load())
+	})
+
+	t.Run("with strict register", func(t *testing.T) {
+		// --- When ---
+		have := NewRegistry(WithStrictRegister())
+
+		// --- Then ---
+		assert.True(t, have.strict.Load())
+	})
+
+	t.Run("with parent", func(t *testing.T) {
+		// --- Given ---
+		base := NewRegistry()
+
+		// --- When ---
+		have := NewRegistry(WithParent(base))
+
+		// --- Then ---
+		assert.Same(t, base, have.parent)
+		assert.Nil(t, base.parent)
+	})
 }
 
 func Test_Registry_Register(t *testing.T) {
@@ -29,7 +66,7 @@ func Test_Registry_Register(t *testing.T) {
 
 		// --- Then ---
 		assert.Nil(t, have)
-		val, _ := assert.HasKey(t, Int, reg.reg)
+		val, _ := assert.HasKey(t, Int, reg.snap.Load().reg)
 		assert.Same(t, cnv, val)
 	})
 
@@ -45,7 +82,7 @@ func Test_Registry_Register(t *testing.T) {
 
 		// --- Then ---
 		assert.Same(t, cnv0, have)
-		val, _ := assert.HasKey(t, Int, reg.reg)
+		val, _ := assert.HasKey(t, Int, reg.snap.Load().reg)
 		assert.Same(t, cnv1, val)
 	})
 
@@ -58,7 +95,382 @@ func Test_Registry_Register(t *testing.T) {
 
 		// --- Then ---
 		assert.Nil(t, have)
-		assert.Len(t, 0, reg.reg)
+		assert.Len(t, 0, reg.snap.Load().reg)
+	})
+}
+
+func Test_Registry_RegisterType(t *testing.T) {
+	t.Run("register not registered", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+
+		// --- When ---
+		have := reg.RegisterType(Int, reflect.TypeFor[int](), cnv)
+
+		// --- Then ---
+		assert.Nil(t, have)
+		val, _ := assert.HasKey(t, Int, reg.snap.Load().reg)
+		assert.Same(t, cnv, val)
+		rt, _ := assert.HasKey(t, Int, reg.snap.Load().typ)
+		assert.Equal(t, reflect.TypeFor[int](), rt)
+	})
+
+	t.Run("register already registered", func(t *testing.T) {
+		// --- Given ---
+		cnv0 := func(value any) (any, error) { return value, nil }
+		cnv1 := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+		reg.RegisterType(Int, reflect.TypeFor[int](), cnv0)
+
+		// --- When ---
+		have := reg.RegisterType(Int, reflect.TypeFor[uint](), cnv1)
+
+		// --- Then ---
+		assert.Same(t, cnv0, have)
+		val, _ := assert.HasKey(t, Int, reg.snap.Load().reg)
+		assert.Same(t, cnv1, val)
+		rt, _ := assert.HasKey(t, Int, reg.snap.Load().typ)
+		assert.Equal(t, reflect.TypeFor[uint](), rt)
+	})
+
+	t.Run("nil type removes the type", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+		reg.RegisterType(Int, reflect.TypeFor[int](), cnv)
+
+		// --- When ---
+		reg.Register(Int, cnv)
+
+		// --- Then ---
+		assert.HasNoKey(t, Int, reg.snap.Load().typ)
+	})
+
+	t.Run("register nil converter", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+
+		// --- When ---
+		have := reg.RegisterType(Int, reflect.TypeFor[int](), nil)
+
+		// --- Then ---
+		assert.Nil(t, have)
+		assert.Len(t, 0, reg.snap.Load().reg)
+		assert.Len(t, 0, reg.snap.Load().typ)
+	})
+}
+
+func Test_Registry_RegisterNumber(t *testing.T) {
+	t.Run("register", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+
+		// --- When ---
+		have := reg.RegisterNumber(Int, reflect.TypeFor[int](), cnv)
+
+		// --- Then ---
+		assert.Nil(t, have)
+		assert.Same(t, cnv, reg.Converter(Int))
+		assert.Equal(t, reflect.TypeFor[int](), reg.GoType(Int))
+		assert.True(t, reg.snap.Load().num[Int])
+	})
+
+	t.Run("register replaced by not accepting numbers", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+		reg.RegisterNumber(Int, reflect.TypeFor[int](), cnv)
+
+		// --- When ---
+		have := reg.RegisterType(Int, reflect.TypeFor[int](), cnv)
+
+		// --- Then ---
+		assert.Same(t, cnv, have)
+		assert.HasNoKey(t, Int, reg.snap.Load().num)
+	})
+
+	t.Run("register nil converter", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+
+		// --- When ---
+		have := reg.RegisterNumber(Int, reflect.TypeFor[int](), nil)
+
+		// --- Then ---
+		assert.Nil(t, have)
+		assert.Len(t, 0, reg.snap.Load().reg)
+		assert.Len(t, 0, reg.snap.Load().num)
+	})
+}
+
+func Test_Registry_decoder(t *testing.T) {
+	t.Run("accepting numbers", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+		reg.RegisterNumber("abc", nil, cnv)
+
+		// --- When ---
+		have := reg.decoder("abc")
+
+		// --- Then ---
+		assert.Same(t, cnv, have)
+	})
+
+	t.Run("not accepting numbers", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+		reg.Register("abc", cnv)
+
+		// --- When ---
+		have := reg.decoder("abc")
+
+		// --- Then ---
+		assert.NotSame(t, cnv, have)
+		assert.Equal(t, 42.0, must.Value(have(json.Number("42"))))
+	})
+
+	t.Run("composite", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+
+		// --- When ---
+		have := reg.decoder("[]uint64")
+
+		// --- Then ---
+		want := []uint64{1<<64 - 1}
+		v := []any{json.Number("18446744073709551615")}
+		assert.Equal(t, want, must.Value(have(v)))
+	})
+
+	t.Run("composite of not accepting numbers", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value float64) (time.Duration, error) {
+			return time.Duration(value) * time.Second, nil
+		}
+		reg := NewRegistry()
+		rt := reflect.TypeFor[time.Duration]()
+		reg.RegisterType("seconds", rt, convert.ToAnyAny(cnv))
+
+		// --- When ---
+		have := reg.decoder("[]seconds")
+
+		// --- Then ---
+		want := []time.Duration{time.Second}
+		assert.Equal(t, want, must.Value(have([]any{json.Number("1")})))
+	})
+
+	t.Run("inherited", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		base := NewRegistry()
+		base.RegisterNumber("abc", nil, cnv)
+		reg := NewRegistry(WithParent(base))
+
+		// --- When ---
+		have := reg.decoder("abc")
+
+		// --- Then ---
+		assert.Same(t, cnv, have)
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		// --- When ---
+		have := NewRegistry().decoder("abc")
+
+		// --- Then ---
+		assert.Nil(t, have)
+	})
+}
+
+func Test_Registry_RegisterType_strict(t *testing.T) {
+	t.Run("not registered", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry(WithStrictRegister())
+
+		// --- When ---
+		have := reg.RegisterType(Int, reflect.TypeFor[int](), cnv)
+
+		// --- Then ---
+		assert.Nil(t, have)
+		assert.Same(t, cnv, reg.Converter(Int))
+	})
+
+	t.Run("conflict panics", func(t *testing.T) {
+		// --- Given ---
+		cnv0 := func(value any) (any, error) { return value, nil }
+		cnv1 := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry(WithStrictRegister())
+		reg.Register(Int, cnv0)
+
+		// --- When ---
+		fn := func() { reg.Register(Int, cnv1) }
+
+		// --- Then ---
+		assert.PanicContain(t, "type name already registered: int", fn)
+		assert.Same(t, cnv0, reg.Converter(Int))
+	})
+
+	t.Run("clone keeps the policy", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := DefaultRegistry(WithStrictRegister()).Clone()
+
+		// --- When ---
+		fn := func() { reg.Register(Int, cnv) }
+
+		// --- Then ---
+		assert.PanicContain(t, "type name already registered: int", fn)
+	})
+}
+
+func Test_Registry_RegisterStrict(t *testing.T) {
+	t.Run("not registered", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+
+		// --- When ---
+		err := reg.RegisterStrict(Int, cnv)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Same(t, cnv, reg.Converter(Int))
+		assert.Nil(t, reg.GoType(Int))
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		// --- Given ---
+		cnv0 := func(value any) (any, error) { return value, nil }
+		cnv1 := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+		reg.Register(Int, cnv0)
+
+		// --- When ---
+		err := reg.RegisterStrict(Int, cnv1)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrConflict, err)
+		var e *ConflictError
+		assert.ErrorAs(t, &e, err)
+		assert.Equal(t, Int, e.Name)
+		assert.Regexp(t, `/registry_test\.go:\d+$`, e.Previous)
+		assert.Regexp(t, `/registry_test\.go:\d+$`, e.Current)
+		assert.Same(t, cnv0, reg.Converter(Int))
+	})
+
+	t.Run("conflict with built-in type", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := DefaultRegistry()
+
+		// --- When ---
+		err := reg.RegisterStrict(Time, cnv)
+
+		// --- Then ---
+		var e *ConflictError
+		assert.ErrorAs(t, &e, err)
+		assert.Equal(t, "jsontype.DefaultRegistry", e.Previous)
+	})
+
+	t.Run("after unregister", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := DefaultRegistry()
+		reg.Unregister(Time)
+
+		// --- When ---
+		err := reg.RegisterStrict(Time, cnv)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Same(t, cnv, reg.Converter(Time))
+	})
+
+	t.Run("nil converter", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+
+		// --- When ---
+		err := reg.RegisterStrict(Int, nil)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.False(t, reg.Has(Int))
+	})
+}
+
+func Test_Registry_MustRegister(t *testing.T) {
+	t.Run("not registered", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+
+		// --- When ---
+		reg.MustRegister(Int, cnv)
+
+		// --- Then ---
+		assert.Same(t, cnv, reg.Converter(Int))
+	})
+
+	t.Run("conflict panics", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := DefaultRegistry()
+
+		// --- When ---
+		fn := func() { reg.MustRegister(Int, cnv) }
+
+		// --- Then ---
+		assert.PanicContain(t, "type name already registered: int", fn)
+	})
+}
+
+func Test_Registry_RegisterEncoder(t *testing.T) {
+	t.Run("register not registered", func(t *testing.T) {
+		// --- Given ---
+		enc := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+
+		// --- When ---
+		have := reg.RegisterEncoder(Int, enc)
+
+		// --- Then ---
+		assert.Nil(t, have)
+		val, _ := assert.HasKey(t, Int, reg.snap.Load().enc)
+		assert.Same(t, enc, val)
+	})
+
+	t.Run("register already registered", func(t *testing.T) {
+		// --- Given ---
+		enc0 := func(value any) (any, error) { return value, nil }
+		enc1 := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+		reg.RegisterEncoder(Int, enc0)
+
+		// --- When ---
+		have := reg.RegisterEncoder(Int, enc1)
+
+		// --- Then ---
+		assert.Same(t, enc0, have)
+		val, _ := assert.HasKey(t, Int, reg.snap.Load().enc)
+		assert.Same(t, enc1, val)
+	})
+
+	t.Run("register nil encoder", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+
+		// --- When ---
+		have := reg.RegisterEncoder(Int, nil)
+
+		// --- Then ---
+		assert.Nil(t, have)
+		assert.Len(t, 0, reg.snap.Load().enc)
 	})
 }
 
@@ -86,4 +498,401 @@ func Test_Registry_Converter(t *testing.T) {
 		// --- Then ---
 		assert.Nil(t, have)
 	})
+
+	t.Run("composite", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+
+		// --- When ---
+		have := reg.Converter("map[string][]uint64")
+
+		// --- Then ---
+		assert.NotNil(t, have)
+	})
+
+	t.Run("composite not registered element", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+
+		// --- When ---
+		have := reg.Converter("[]uint64")
+
+		// --- Then ---
+		assert.Nil(t, have)
+	})
+}
+
+func Test_Registry_GoType(t *testing.T) {
+	t.Run("registered", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+		reg.RegisterType(Int, reflect.TypeFor[int](), cnv)
+
+		// --- When ---
+		have := reg.GoType(Int)
+
+		// --- Then ---
+		assert.Equal(t, reflect.TypeFor[int](), have)
+	})
+
+	t.Run("registered without type", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+		reg.Register(Int, cnv)
+
+		// --- When ---
+		have := reg.GoType(Int)
+
+		// --- Then ---
+		assert.Nil(t, have)
+	})
+
+	t.Run("composite", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+
+		// --- When ---
+		have := reg.GoType("map[string][2]time.Time")
+
+		// --- Then ---
+		assert.Equal(t, reflect.TypeFor[map[string][2]time.Time](), have)
+	})
+
+	t.Run("not registered", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+
+		// --- When ---
+		have := reg.GoType(Int)
+
+		// --- Then ---
+		assert.Nil(t, have)
+	})
+}
+
+func Test_Registry_Encoder(t *testing.T) {
+	t.Run("registered", func(t *testing.T) {
+		// --- Given ---
+		enc := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+		reg.RegisterEncoder(Int, enc)
+
+		// --- When ---
+		have := reg.Encoder(Int)
+
+		// --- Then ---
+		assert.Same(t, enc, have)
+	})
+
+	t.Run("composite", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+
+		// --- When ---
+		have := reg.Encoder("map[string][]time.Duration")
+
+		// --- Then ---
+		assert.NotNil(t, have)
+	})
+
+	t.Run("composite without element encoder", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+
+		// --- When ---
+		have := reg.Encoder("[]int")
+
+		// --- Then ---
+		assert.Nil(t, have)
+	})
+
+	t.Run("not registered", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+
+		// --- When ---
+		have := reg.Encoder(Int)
+
+		// --- Then ---
+		assert.Nil(t, have)
+	})
+}
+
+func Test_Registry_Unregister(t *testing.T) {
+	t.Run("registered", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+		cnv := reg.Converter(Duration)
+
+		// --- When ---
+		have := reg.Unregister(Duration)
+
+		// --- Then ---
+		assert.Same(t, cnv, have)
+		assert.Nil(t, reg.Converter(Duration))
+		assert.Nil(t, reg.GoType(Duration))
+		assert.Nil(t, reg.Encoder(Duration))
+		assert.Len(t, 18, reg.snap.Load().reg)
+		assert.Len(t, 17, reg.snap.Load().typ)
+		assert.Len(t, 1, reg.snap.Load().enc)
+		assert.Len(t, 17, reg.snap.Load().app)
+	})
+
+	t.Run("fast path is removed", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+
+		// --- When ---
+		reg.Unregister(Duration)
+
+		// --- Then ---
+		have := must.Value(Marshal(reg, New(time.Second)))
+		want := `{"type":"time.Duration","value":1000000000}`
+		assert.Equal(t, want, string(have))
+	})
+
+	t.Run("not registered", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+		snap := reg.snap.Load()
+
+		// --- When ---
+		have := reg.Unregister("abc")
+
+		// --- Then ---
+		assert.Nil(t, have)
+		assert.Len(t, 19, reg.snap.Load().reg)
+		assert.Same(t, snap.reg, reg.snap.Load().reg)
+	})
+}
+
+func Test_Registry_Has(t *testing.T) {
+	// --- Given ---
+	reg := DefaultRegistry()
+
+	// --- Then ---
+	assert.True(t, reg.Has(Int))
+	assert.True(t, reg.Has(Nil))
+	assert.False(t, reg.Has("[]int"))
+	assert.False(t, reg.Has("abc"))
+}
+
+func Test_Registry_Len(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+
+		// --- Then ---
+		assert.Equal(t, 0, reg.Len())
+	})
+
+	t.Run("default", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+
+		// --- Then ---
+		assert.Equal(t, 19, reg.Len())
+	})
+}
+
+func Test_Registry_Names(t *testing.T) {
+	t.Run("sorted", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+		reg.Register(String, cnv)
+		reg.Register(Bool, cnv)
+		reg.Register(Int, cnv)
+
+		// --- When ---
+		have := reg.Names()
+
+		// --- Then ---
+		assert.Equal(t, []string{Bool, Int, String}, have)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+
+		// --- When ---
+		have := reg.Names()
+
+		// --- Then ---
+		assert.Len(t, 0, have)
+	})
+}
+
+func Test_Registry_All(t *testing.T) {
+	t.Run("all converters", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+
+		// --- When ---
+		var names []string
+		for name, cnv := range reg.All() {
+			names = append(names, name)
+			assert.Same(t, reg.Converter(name), cnv)
+		}
+
+		// --- Then ---
+		assert.Equal(t, reg.Names(), names)
+	})
+
+	t.Run("break", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+
+		// --- When ---
+		var names []string
+		for name := range reg.All() {
+			names = append(names, name)
+			if len(names) == 2 {
+				break
+			}
+		}
+
+		// --- Then ---
+		assert.Equal(t, []string{Bool, Byte}, names)
+	})
+
+	t.Run("registrations during iteration are not visible", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+		reg.Register(Int, cnv)
+
+		// --- When ---
+		var names []string
+		for name := range reg.All() {
+			names = append(names, name)
+			reg.Register(Bool, cnv)
+			reg.Unregister(Int)
+		}
+
+		// --- Then ---
+		assert.Equal(t, []string{Int}, names)
+		assert.Equal(t, []string{Bool}, reg.Names())
+	})
+}
+
+func Test_Registry_Clone(t *testing.T) {
+	t.Run("copies registrations", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+
+		// --- When ---
+		have := reg.Clone()
+
+		// --- Then ---
+		assert.Equal(t, reg.Names(), have.Names())
+		assert.Same(t, reg.Converter("circle"), have.Converter("circle"))
+		assert.Same(t, reg.Encoder("circle"), have.Encoder("circle"))
+		assert.Equal(t, reflect.TypeFor[TCircle](), have.GoType("circle"))
+	})
+
+	t.Run("independent", func(t *testing.T) {
+		// --- Given ---
+		cnv := func(value any) (any, error) { return value, nil }
+		reg := NewRegistry()
+		reg.Register(Int, cnv)
+
+		// --- When ---
+		have := reg.Clone()
+
+		// --- Then ---
+		have.Register(Bool, cnv)
+		reg.Register(String, cnv)
+		reg.Unregister(Int)
+		assert.Equal(t, []string{String}, reg.Names())
+		assert.Equal(t, []string{Bool, Int}, have.Names())
+	})
+}
+
+func Test_Registry_concurrency(t *testing.T) {
+	t.Run("register returns previous converters", func(t *testing.T) {
+		// --- Given ---
+		const n = 100
+		reg := NewRegistry()
+		olds := make([]convert.AnyToAny, n)
+
+		// --- When ---
+		var wg sync.WaitGroup
+		for i := range n {
+			cnv := func(any) (any, error) { return i, nil }
+			wg.Go(func() { olds[i] = reg.Register(Int, cnv) })
+		}
+		wg.Wait()
+
+		// --- Then ---
+		// Each registration replaced exactly one other, so the previous
+		// converters together with the last one form the full set.
+		seen := make(map[int]int, n)
+		seen[must.Value(reg.Converter(Int)(nil)).(int)]++
+		var nils int
+		for _, old := range olds {
+			if old == nil {
+				nils++
+				continue
+			}
+			seen[must.Value(old(nil)).(int)]++
+		}
+		assert.Equal(t, 1, nils)
+		assert.Len(t, n, seen)
+		for i := range n {
+			assert.Equal(t, 1, seen[i])
+		}
+	})
+
+	t.Run("lookups during registrations", func(t *testing.T) {
+		// --- Given ---
+		reg := DefaultRegistry()
+		enc := func(v any) (any, error) { return v, nil }
+
+		// --- When ---
+		var wg sync.WaitGroup
+		var missing atomic.Int32
+		for i := range 50 {
+			name := fmt.Sprintf("type%d", i)
+			rt := reflect.TypeFor[int]()
+			wg.Go(func() { reg.RegisterType(name, rt, enc) })
+			wg.Go(func() { reg.RegisterEncoder(name, enc) })
+			wg.Go(func() { reg.Clone().Unregister(Uint64) })
+			wg.Go(func() {
+				if reg.Converter(Uint64) == nil || reg.Encoder(Time) == nil ||
+					reg.GoType(Int) == nil || reg.Converter("[]int") == nil {
+					missing.Add(1)
+				}
+				for name := range reg.All() {
+					if !reg.Has(name) {
+						missing.Add(1)
+					}
+				}
+			})
+		}
+		wg.Wait()
+
+		// --- Then ---
+		assert.Equal(t, int32(0), missing.Load())
+		assert.Equal(t, 69, reg.Len())
+		for i := range 50 {
+			name := fmt.Sprintf("type%d", i)
+			assert.True(t, reg.Has(name))
+			assert.NotNil(t, reg.Converter(name))
+			assert.NotNil(t, reg.Encoder(name))
+			assert.Equal(t, reflect.TypeFor[int](), reg.GoType(name))
+		}
+	})
+}
+
+func Benchmark_Registry_Converter(b *testing.B) {
+	reg := DefaultRegistry()
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = reg.Converter(Uint64)
+		}
+	})
 }
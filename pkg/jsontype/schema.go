@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/ctx42/convert/pkg/convert"
+)
+
+// Schema maps paths in a plain JSON document to type names. Paths are either
+// JSON Pointers (RFC 6901), for example "/items/0/id", or dotted paths, for
+// example "items.0.id". The empty path refers to the whole document.
+type Schema map[string]string
+
+// pointers returns the schema with all paths represented as JSON Pointers.
+// Returns joined errors for all invalid paths and type names not known to the
+// registry.
+func (s Schema) pointers(reg *Registry) (map[string]string, error) {
+	types := make(map[string]string, len(s))
+	var errs []error
+	for _, pth := range slices.Sorted(maps.Keys(s)) {
+		typ := s[pth]
+		ptr, err := toPointer(pth)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("path %q: %w", pth, err))
+			continue
+		}
+		if reg.Converter(typ) == nil {
+			err = fmt.Errorf("path %q: %w: %s", pth, convert.ErrUnsType, typ)
+			errs = append(errs, err)
+			continue
+		}
+		if _, ok := types[ptr]; ok {
+			format := "path %q: %w: duplicate path"
+			errs = append(errs, fmt.Errorf(format, pth, convert.ErrInvFormat))
+			continue
+		}
+		types[ptr] = typ
+	}
+	return types, errors.Join(errs...)
+}
+
+// DecodeWithSchema decodes plain JSON and converts values at paths listed in
+// the schema with converters from the [Registry]. Numbers at other paths are
+// decoded as float64. Paths which are not present in the document are
+// skipped. Returns joined errors for all invalid paths, unknown type names and
+// failed conversions, each prefixed with the path it concerns.
+func DecodeWithSchema(reg *Registry, schema Schema, data []byte) (any, error) {
+	types, err := schema.pointers(reg)
+	if err != nil {
+		return nil, fmt.Errorf("jsontype: %w", err)
+	}
+	v, err := decodeNumber(data)
+	if err != nil {
+		return nil, fmt.Errorf("jsontype: %w", err)
+	}
+	def := newOptions(WithRegistry(reg))
+	if v, err = restoreTree(def, "", v, types); err != nil {
+		return nil, fmt.Errorf("jsontype: %w", err)
+	}
+	return v, nil
+}
+
+// toPointer returns the path as JSON Pointer. JSON Pointers are returned as
+// they are after checking their escape sequences.
+func toPointer(pth string) (string, error) {
+	if pth == "" {
+		return "", nil
+	}
+	if !strings.HasPrefix(pth, "/") {
+		tokens := strings.Split(pth, ".")
+		for i, token := range tokens {
+			tokens[i] = escapeToken(token)
+		}
+		return "/" + strings.Join(tokens, "/"), nil
+	}
+	if _, err := splitPointer(pth); err != nil {
+		return "", err
+	}
+	return pth, nil
+}
@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ctx42/convert/pkg/convert"
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+func Test_Schema_pointers(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		s := Schema{"": "[]uint", "/a/b": "int", "c.0.d/e": "uint8"}
+
+		// --- When ---
+		have, err := s.pointers(registry)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := map[string]string{
+			"":          "[]uint",
+			"/a/b":      "int",
+			"/c/0/d~1e": "uint8",
+		}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("error - all invalid paths", func(t *testing.T) {
+		// --- Given ---
+		s := Schema{
+			"/a~2": "int",
+			"/b":   "unknown",
+			"/c":   "int",
+			"c":    "int",
+		}
+
+		// --- When ---
+		_, err := s.pointers(registry)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvFormat, err)
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		wMsg := "path \"/a~2\": invalid format: " +
+			"invalid JSON Pointer escape sequence\n" +
+			"path \"/b\": unsupported type: unknown\n" +
+			"path \"c\": invalid format: duplicate path"
+		assert.ErrorEqual(t, wMsg, err)
+	})
+}
+
+func Test_DecodeWithSchema(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		data := `{
+			"id": 18446744073709551615,
+			"items": [{"ttl": "1s", "n": 1}, {"ttl": "2s", "n": 2}],
+			"other": 1
+		}`
+		schema := Schema{
+			"id":           "uint64",
+			"/items/0/ttl": "time.Duration",
+			"items.1.ttl":  "time.Duration",
+			"items.1.n":    "int8",
+			"missing":      "uint",
+		}
+
+		// --- When ---
+		have, err := DecodeWithSchema(registry, schema, []byte(data))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := map[string]any{
+			"id": uint64(1<<64 - 1),
+			"items": []any{
+				map[string]any{"ttl": time.Second, "n": 1.0},
+				map[string]any{"ttl": 2 * time.Second, "n": int8(2)},
+			},
+			"other": 1.0,
+		}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("whole document", func(t *testing.T) {
+		// --- Given ---
+		schema := Schema{"": "[]uint16"}
+
+		// --- When ---
+		have, err := DecodeWithSchema(registry, schema, []byte(`[1, 2]`))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []uint16{1, 2}, have)
+	})
+
+	t.Run("custom registry", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+		schema := Schema{"a": "circle"}
+
+		// --- When ---
+		have, err := DecodeWithSchema(reg, schema, []byte(`{"a": {"r": 1}}`))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"a": TCircle{R: 1}}, have)
+	})
+
+	t.Run("error - conversions", func(t *testing.T) {
+		// --- Given ---
+		data := `{"a": 256, "b": [1, -1], "c": 1}`
+		schema := Schema{"a": "uint8", "b.1": "uint", "c": "uint"}
+
+		// --- When ---
+		have, err := DecodeWithSchema(registry, schema, []byte(data))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvRange, err)
+		assert.ErrorContain(t, "jsontype: path \"/a\": ", err)
+		assert.ErrorContain(t, "\npath \"/b/1\": ", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - unknown type name", func(t *testing.T) {
+		// --- Given ---
+		schema := Schema{"a": "unknown"}
+
+		// --- When ---
+		have, err := DecodeWithSchema(registry, schema, []byte(`{}`))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		wMsg := "jsontype: path \"a\": unsupported type: unknown"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - invalid JSON", func(t *testing.T) {
+		// --- When ---
+		have, err := DecodeWithSchema(registry, nil, []byte(`{!}`))
+
+		// --- Then ---
+		assert.ErrorContain(t, "jsontype: invalid character", err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_toPointer_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		pth  string
+		want string
+	}{
+		{"empty", "", ""},
+		{"pointer", "/a/0", "/a/0"},
+		{"pointer with escapes", "/a~0~1", "/a~0~1"},
+		{"dotted", "a.0.b", "/a/0/b"},
+		{"dotted with escapes", "a~.b/c", "/a~0/b~1c"},
+		{"dotted empty token", "a..b", "/a//b"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- When ---
+			have, err := toPointer(tc.pth)
+
+			// --- Then ---
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
+
+func Test_toPointer(t *testing.T) {
+	t.Run("error - invalid escape", func(t *testing.T) {
+		// --- When ---
+		have, err := toPointer("/a~")
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvFormat, err)
+		wMsg := "invalid format: invalid JSON Pointer escape sequence"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Empty(t, have)
+	})
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
+)
+
+func Test_appendFloat_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		val  float64
+		bits int
+	}{
+		{"zero", 0, 64},
+		{"negative zero", math.Copysign(0, -1), 64},
+		{"fraction", 0.1, 64},
+		{"small", 1e-7, 64},
+		{"small negative", -1.5e-10, 64},
+		{"big", 1e21, 64},
+		{"below big", 1e20, 64},
+		{"max", math.MaxFloat64, 64},
+		{"smallest", math.SmallestNonzeroFloat64, 64},
+		{"float32 fraction", float64(float32(0.1)), 32},
+		{"float32 small", float64(float32(1e-7)), 32},
+		{"float32 max", math.MaxFloat32, 32},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- Given ---
+			var want []byte
+			if tc.bits == 32 {
+				want = must.Value(json.Marshal(float32(tc.val)))
+			} else {
+				want = must.Value(json.Marshal(tc.val))
+			}
+
+			// --- When ---
+			have, ok := appendFloat(nil, tc.val, tc.bits)
+
+			// --- Then ---
+			assert.True(t, ok)
+			assert.Equal(t, string(want), string(have))
+		})
+	}
+}
+
+func Test_appendFloat(t *testing.T) {
+	t.Run("appends", func(t *testing.T) {
+		// --- When ---
+		have, ok := appendFloat([]byte("a"), 1.5, 64)
+
+		// --- Then ---
+		assert.True(t, ok)
+		assert.Equal(t, "a1.5", string(have))
+	})
+
+	t.Run("NaN", func(t *testing.T) {
+		// --- When ---
+		have, ok := appendFloat([]byte("a"), math.NaN(), 64)
+
+		// --- Then ---
+		assert.False(t, ok)
+		assert.Equal(t, "a", string(have))
+	})
+
+	t.Run("infinity", func(t *testing.T) {
+		// --- When ---
+		have, ok := appendFloat(nil, math.Inf(-1), 64)
+
+		// --- Then ---
+		assert.False(t, ok)
+		assert.Len(t, 0, have)
+	})
+}
+
+func Test_appendDuration_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		val time.Duration
+	}{
+		{"zero", 0},
+		{"nanoseconds", 1},
+		{"microseconds", 1100 * time.Nanosecond},
+		{"milliseconds", 2200 * time.Microsecond},
+		{"seconds", 3300 * time.Millisecond},
+		{"minutes", 4*time.Minute + 5*time.Second},
+		{"hours", 5*time.Hour + 6*time.Minute + 7001*time.Millisecond},
+		{"negative", -time.Microsecond},
+		{"min", math.MinInt64},
+		{"max", math.MaxInt64},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- When ---
+			have := appendDuration([]byte("a"), tc.val)
+
+			// --- Then ---
+			assert.Equal(t, "a"+tc.val.String(), string(have))
+		})
+	}
+}
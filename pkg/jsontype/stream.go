@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Encoder writes JSON representations of values to an output stream.
+type Encoder struct {
+	enc *json.Encoder // Underlying encoder.
+	def *Options      // Encoder options.
+}
+
+// NewEncoder returns a new encoder that writes to w. By default, the
+// package-level registry is used, use [WithRegistry] to provide a custom one.
+func NewEncoder(w io.Writer, opts ...Option) *Encoder {
+	return &Encoder{enc: json.NewEncoder(w), def: newOptions(opts...)}
+}
+
+// Encode writes the JSON representation of the value to the stream, followed
+// by a newline character.
+func (e *Encoder) Encode(val *Value) error {
+	v, err := envelope(e.def.reg, val)
+	if err != nil {
+		return err
+	}
+	return e.enc.Encode(v)
+}
+
+// SetIndent instructs the encoder to format each subsequent encoded value as
+// if indented by [json.Indent].
+func (e *Encoder) SetIndent(prefix, indent string) {
+	e.enc.SetIndent(prefix, indent)
+}
+
+// SetEscapeHTML specifies whether problematic HTML characters should be
+// escaped inside JSON quoted strings. See [json.Encoder.SetEscapeHTML].
+func (e *Encoder) SetEscapeHTML(on bool) { e.enc.SetEscapeHTML(on) }
+
+// Decoder reads and decodes JSON representations of values from an input
+// stream.
+type Decoder struct {
+	dec *json.Decoder // Underlying decoder.
+	def *Options      // Decoder options.
+}
+
+// NewDecoder returns a new decoder that reads from r. By default, the
+// package-level registry is used, use [WithRegistry] to provide a custom one.
+// The [WithStrict], [WithUnknown] and [WithAllowedTypes] options are
+// supported.
+func NewDecoder(r io.Reader, opts ...Option) *Decoder {
+	return &Decoder{dec: json.NewDecoder(r), def: newOptions(opts...)}
+}
+
+// Decode reads the next JSON representation of the value from its input and
+// stores it in the value. Returns [io.EOF] when there are no more values.
+func (d *Decoder) Decode(val *Value) error {
+	var raw json.RawMessage
+	if err := d.dec.Decode(&raw); err != nil {
+		if errors.Is(err, io.EOF) {
+			return err
+		}
+		return fmt.Errorf("jsontype: %w", err)
+	}
+	return unmarshal(d.def, raw, val)
+}
+
+// DisallowUnknownFields turns on the strict mode, the same way as the
+// [WithStrict] option does.
+func (d *Decoder) DisallowUnknownFields() { d.def.strict = true }
+
+// More reports whether there is another element in the current array or
+// object being parsed.
+func (d *Decoder) More() bool { return d.dec.More() }
+
+// Buffered returns a reader of the data remaining in the decoder's buffer.
+func (d *Decoder) Buffered() io.Reader { return d.dec.Buffered() }
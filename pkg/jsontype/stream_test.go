@@ -0,0 +1,296 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ctx42/convert/pkg/convert"
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
+)
+
+func Test_NewEncoder(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		// --- When ---
+		have := NewEncoder(&bytes.Buffer{})
+
+		// --- Then ---
+		assert.NotNil(t, have.enc)
+		assert.Same(t, registry, have.def.reg)
+	})
+
+	t.Run("with options", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+
+		// --- When ---
+		have := NewEncoder(&bytes.Buffer{}, WithRegistry(reg))
+
+		// --- Then ---
+		assert.Same(t, reg, have.def.reg)
+	})
+}
+
+func Test_Encoder_Encode(t *testing.T) {
+	t.Run("stream", func(t *testing.T) {
+		// --- Given ---
+		buf := &bytes.Buffer{}
+		enc := NewEncoder(buf)
+
+		// --- When ---
+		err0 := enc.Encode(New(uint64(1<<64 - 1)))
+		err1 := enc.Encode(New(time.Second))
+
+		// --- Then ---
+		assert.NoError(t, err0)
+		assert.NoError(t, err1)
+		want := `{"type":"uint64","value":18446744073709551615}` + "\n" +
+			`{"type":"time.Duration","value":"1s"}` + "\n"
+		assert.Equal(t, want, buf.String())
+	})
+
+	t.Run("custom registry", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+		buf := &bytes.Buffer{}
+		enc := NewEncoder(buf, WithRegistry(reg))
+
+		// --- When ---
+		err := enc.Encode(&Value{typ: "circle", val: TCircle{R: 1}})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := `{"type":"circle","value":{"r":1}}` + "\n"
+		assert.Equal(t, want, buf.String())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		// --- Given ---
+		buf := &bytes.Buffer{}
+		enc := NewEncoder(buf)
+
+		// --- When ---
+		err := enc.Encode(&Value{})
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvValue, err)
+		assert.Empty(t, buf.String())
+	})
+}
+
+func Test_Encoder_SetIndent(t *testing.T) {
+	// --- Given ---
+	buf := &bytes.Buffer{}
+	enc := NewEncoder(buf)
+
+	// --- When ---
+	enc.SetIndent("", "  ")
+
+	// --- Then ---
+	must.Nil(enc.Encode(New(uint(1))))
+	want := "{\n  \"type\": \"uint\",\n  \"value\": 1\n}\n"
+	assert.Equal(t, want, buf.String())
+}
+
+func Test_Encoder_SetEscapeHTML(t *testing.T) {
+	// --- Given ---
+	buf := &bytes.Buffer{}
+	enc := NewEncoder(buf)
+
+	// --- When ---
+	enc.SetEscapeHTML(false)
+
+	// --- Then ---
+	must.Nil(enc.Encode(New("<a>")))
+	assert.Equal(t, `{"type":"string","value":"<a>"}`+"\n", buf.String())
+}
+
+func Test_NewDecoder(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		// --- When ---
+		have := NewDecoder(strings.NewReader(""))
+
+		// --- Then ---
+		assert.NotNil(t, have.dec)
+		assert.Same(t, registry, have.def.reg)
+		assert.False(t, have.def.strict)
+	})
+
+	t.Run("with options", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+		opts := []Option{WithRegistry(reg), WithStrict()}
+
+		// --- When ---
+		have := NewDecoder(strings.NewReader(""), opts...)
+
+		// --- Then ---
+		assert.Same(t, reg, have.def.reg)
+		assert.True(t, have.def.strict)
+	})
+}
+
+func Test_Decoder_Decode(t *testing.T) {
+	t.Run("stream", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint64", "value": 18446744073709551615}
+			{"type": "time.Duration", "value": "1s"}`
+		dec := NewDecoder(strings.NewReader(data))
+
+		// --- When ---
+		val0, val1 := &Value{}, &Value{}
+		err0 := dec.Decode(val0)
+		err1 := dec.Decode(val1)
+		err2 := dec.Decode(&Value{})
+
+		// --- Then ---
+		assert.NoError(t, err0)
+		assert.Equal(t, uint64(1<<64-1), val0.val)
+		assert.NoError(t, err1)
+		assert.Equal(t, time.Second, val1.val)
+		assert.Same(t, io.EOF, err2)
+	})
+
+	t.Run("custom registry", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+		data := `{"type": "circle", "value": {"r": 1}}`
+		dec := NewDecoder(strings.NewReader(data), WithRegistry(reg))
+
+		// --- When ---
+		val := &Value{}
+		err := dec.Decode(val)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, TCircle{R: 1}, val.val)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "money", "value": 1}`
+		dec := NewDecoder(strings.NewReader(data), WithUnknown())
+
+		// --- When ---
+		val := &Value{}
+		err := dec.Decode(val)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "money", val.typ)
+		assert.Equal(t, data, string(must.Value(Marshal(registry, val))))
+	})
+
+	t.Run("not strict", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint", "value": 1, "other": 2}`
+		dec := NewDecoder(strings.NewReader(data))
+
+		// --- When ---
+		val := &Value{}
+		err := dec.Decode(val)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), val.val)
+	})
+
+	t.Run("error - strict", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint", "value": 1, "other": 2}`
+		dec := NewDecoder(strings.NewReader(data), WithStrict())
+
+		// --- When ---
+		err := dec.Decode(&Value{})
+
+		// --- Then ---
+		wMsg := "jsontype: json: unknown field \"other\""
+		assert.ErrorEqual(t, wMsg, err)
+	})
+
+	t.Run("error - invalid JSON", func(t *testing.T) {
+		// --- Given ---
+		dec := NewDecoder(strings.NewReader(`{!}`))
+
+		// --- When ---
+		err := dec.Decode(&Value{})
+
+		// --- Then ---
+		assert.ErrorContain(t, "jsontype: invalid character", err)
+	})
+
+	t.Run("error - type not allowed", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint8", "value": 1} {"type": "int", "value": 2}`
+		dec := NewDecoder(strings.NewReader(data), WithAllowedTypes(Int))
+
+		// --- When ---
+		val := &Value{}
+		err0 := dec.Decode(val)
+		err1 := dec.Decode(val)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrTypeNotAllowed, err0)
+		assert.NoError(t, err1)
+		assert.Equal(t, 2, val.val)
+	})
+
+	t.Run("error - conversion", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint8", "value": 256}`
+		dec := NewDecoder(strings.NewReader(data))
+
+		// --- When ---
+		err := dec.Decode(&Value{})
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvRange, err)
+	})
+}
+
+func Test_Decoder_DisallowUnknownFields(t *testing.T) {
+	// --- Given ---
+	data := `{"type": "uint", "value": 1, "other": 2}`
+	dec := NewDecoder(strings.NewReader(data))
+
+	// --- When ---
+	dec.DisallowUnknownFields()
+
+	// --- Then ---
+	assert.True(t, dec.def.strict)
+	err := dec.Decode(&Value{})
+	assert.ErrorEqual(t, "jsontype: json: unknown field \"other\"", err)
+}
+
+func Test_Decoder_More(t *testing.T) {
+	// --- Given ---
+	dec := NewDecoder(strings.NewReader(`{"type": "uint", "value": 1}`))
+
+	// --- When ---
+	more0 := dec.More()
+	must.Nil(dec.Decode(&Value{}))
+	more1 := dec.More()
+
+	// --- Then ---
+	assert.True(t, more0)
+	assert.False(t, more1)
+}
+
+func Test_Decoder_Buffered(t *testing.T) {
+	// --- Given ---
+	data := `{"type": "uint", "value": 1} rest`
+	dec := NewDecoder(strings.NewReader(data))
+	must.Nil(dec.Decode(&Value{}))
+
+	// --- When ---
+	have := dec.Buffered()
+
+	// --- Then ---
+	assert.Equal(t, " rest", string(must.Value(io.ReadAll(have))))
+}
@@ -0,0 +1,254 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/ctx42/convert/pkg/convert"
+)
+
+// RegisterStruct registers a converter, an encoder and the Go type for the
+// struct type T under the given type name.
+//
+// The struct is encoded as a JSON object with field names taken from the
+// `json` tags, the same way [json.Marshal] does it. Fields of registered
+// types (including other registered structs) are encoded and decoded with
+// their registered encoders and converters, so they are restored to their
+// exact Go types. Fields of other types are decoded with [json.Unmarshal].
+//
+// By default, the package-level registry is used, use [WithRegistry] to
+// provide a custom one. Use [WithStrict] to reject unknown fields, it applies
+// to all values decoded with the registered converter. Returns an error when
+// T is not a struct or when it has fields promoted through embedded pointers,
+// which are not supported. For registries created with [WithStrictRegister],
+// returns [ConflictError] when the type name is already registered.
+//
+// Use [NewValue] to create values of the struct type, [New] uses the name
+// returned by [reflect.Type.String] as the type name.
+func RegisterStruct[T any](name string, opts ...Option) error {
+	rt := reflect.TypeFor[T]()
+	if rt.Kind() != reflect.Struct {
+		format := "RegisterStruct: %w: expected struct got %s"
+		return fmt.Errorf(format, convert.ErrInvType, rt)
+	}
+	def := newOptions(opts...)
+	fields, err := structFields(rt)
+	if err != nil {
+		return fmt.Errorf("RegisterStruct: %w", err)
+	}
+	cnv := structConverter(def, rt, fields)
+	_, err = def.reg.register(name, rt, cnv, true, false)
+	if err != nil {
+		return fmt.Errorf("RegisterStruct: %w", err)
+	}
+	def.reg.RegisterEncoder(name, structEncoder(def.reg, rt, fields))
+	return nil
+}
+
+// structField represents an encodable struct field.
+type structField struct {
+	name      string       // JSON name.
+	index     []int        // Index for [reflect.Value.FieldByIndex].
+	typ       reflect.Type // Field type.
+	omitEmpty bool         // Field has the "omitempty" tag option.
+}
+
+// structFields returns encodable fields of the struct type. It follows the
+// [json.Marshal] rules for exported fields, `json` tags and embedded structs.
+// Returns an error when fields are promoted through embedded pointers, which
+// are not supported.
+func structFields(rt reflect.Type) ([]structField, error) {
+	var fields []structField
+	for _, f := range reflect.VisibleFields(rt) {
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && isStruct(f.Type) {
+			if f.Type.Kind() == reflect.Pointer && promoted(rt, f.Index) {
+				format := "%w: embedded pointer %s"
+				return nil, fmt.Errorf(format, convert.ErrUnsType, f.Type)
+			}
+			continue // Fields of embedded structs are visible on their own.
+		}
+		if !f.IsExported() || !promoted(rt, f.Index) {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		field := structField{
+			name:      name,
+			index:     f.Index,
+			typ:       f.Type,
+			omitEmpty: slices.Contains(strings.Split(opts, ","), "omitempty"),
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// isStruct returns true for struct types and pointers to struct types.
+func isStruct(rt reflect.Type) bool {
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	return rt.Kind() == reflect.Struct
+}
+
+// promoted returns true when all the struct fields on the path to the field
+// with the given index are embedded structs, which fields are promoted to the
+// outer struct by [json.Marshal]. Embedded pointers are not supported.
+func promoted(rt reflect.Type, index []int) bool {
+	for _, i := range index[:len(index)-1] {
+		f := rt.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.Anonymous || name != "" || f.Type.Kind() != reflect.Struct {
+			return false
+		}
+		rt = f.Type
+	}
+	return true
+}
+
+// structConverter returns a converter for the struct type. It expects a JSON
+// object or null.
+func structConverter(
+	def *Options,
+	rt reflect.Type,
+	fields []structField,
+) convert.AnyToAny {
+
+	return func(value any) (any, error) {
+		if value == nil {
+			return reflect.Zero(rt).Interface(), nil
+		}
+		if reflect.TypeOf(value) == rt {
+			return value, nil
+		}
+		src, ok := value.(map[string]any)
+		if !ok {
+			return nil, invTypeError(rt, value)
+		}
+		if def.strict {
+			if err := unknownField(src, fields); err != nil {
+				return nil, err
+			}
+		}
+		dst := reflect.New(rt).Elem()
+		for _, f := range fields {
+			v, ok := src[f.name]
+			if !ok {
+				continue
+			}
+			fv := dst.FieldByIndex(f.index)
+			if err := setField(def.reg, fv, v); err != nil {
+				return nil, fmt.Errorf("field %s: %w", f.name, err)
+			}
+		}
+		return dst.Interface(), nil
+	}
+}
+
+// unknownField returns an error for the first (in lexical order) key in the
+// map which is not a name of any of the fields.
+func unknownField(src map[string]any, fields []structField) error {
+	for _, key := range slices.Sorted(maps.Keys(src)) {
+		known := slices.ContainsFunc(fields, func(f structField) bool {
+			return f.name == key
+		})
+		if !known {
+			return fmt.Errorf("%w: unknown field %q", convert.ErrInvFormat, key)
+		}
+	}
+	return nil
+}
+
+// setField converts the value to the field type and sets it. When the field
+// type is registered, its converter is used, otherwise the value is decoded
+// with [json.Unmarshal]. Nil values (JSON null) are skipped, so the field
+// keeps its zero value, as with [json.Unmarshal].
+func setField(reg *Registry, dst reflect.Value, value any) error {
+	if value == nil {
+		return nil
+	}
+	if name := reg.typeName(dst.Type()); name != "" {
+		if cnv := reg.decoder(name); cnv != nil {
+			return setElem(dst, cnv, value)
+		}
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst.Addr().Interface())
+}
+
+// structEncoder returns an encoder for the struct type. It encodes the struct
+// as a map with JSON field names as keys.
+func structEncoder(
+	reg *Registry,
+	rt reflect.Type,
+	fields []structField,
+) convert.AnyToAny {
+
+	return func(value any) (any, error) {
+		rv := reflect.ValueOf(value)
+		if !rv.IsValid() || rv.Type() != rt {
+			return nil, invTypeError(rt, value)
+		}
+		dst := make(map[string]any, len(fields))
+		for _, f := range fields {
+			fv := rv.FieldByIndex(f.index)
+			if f.omitEmpty && isEmptyValue(fv) {
+				continue
+			}
+			v, err := encodeField(reg, fv)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", f.name, err)
+			}
+			dst[f.name] = v
+		}
+		return dst, nil
+	}
+}
+
+// encodeField encodes the field value with the encoder registered for its
+// type. Returns the raw value when there is no encoder.
+func encodeField(reg *Registry, fv reflect.Value) (any, error) {
+	name := reg.typeName(fv.Type())
+	if name == "" {
+		return fv.Interface(), nil
+	}
+	enc := reg.Encoder(name)
+	if enc == nil {
+		return fv.Interface(), nil
+	}
+	return encodeValue(enc, fv)
+}
+
+// isEmptyValue returns true if the value is empty as defined by the
+// "omitempty" option of [json.Marshal].
+func isEmptyValue(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return rv.IsZero()
+	default:
+		return false
+	}
+}
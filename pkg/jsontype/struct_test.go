@@ -0,0 +1,646 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ctx42/convert/pkg/convert"
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
+)
+
+// TInner is a struct used in tests.
+type TInner struct {
+	At  time.Time     `json:"at"`
+	TTL time.Duration `json:"ttl,omitempty"`
+}
+
+// TOuter is a struct used in tests.
+type TOuter struct {
+	ID      uint64            `json:"id"`
+	Name    string            `json:"name"`
+	Inner   TInner            `json:"inner"`
+	Inners  []TInner          `json:"inners,omitempty"`
+	Counts  map[string]uint64 `json:"counts,omitempty"`
+	Any     any               `json:"any,omitempty"`
+	Skipped int               `json:"-"`
+	private int
+}
+
+// TEmbedded is a struct used in tests.
+type TEmbedded struct {
+	TInner
+	Other uint64
+}
+
+// structRegistry returns the default registry with test structs registered.
+func structRegistry(t *testing.T, opts ...Option) *Registry {
+	t.Helper()
+	reg := DefaultRegistry()
+	opts = append([]Option{WithRegistry(reg)}, opts...)
+	must.Nil(RegisterStruct[TInner]("inner", opts...))
+	must.Nil(RegisterStruct[TOuter]("outer", opts...))
+	must.Nil(RegisterStruct[TEmbedded]("embedded", opts...))
+	return reg
+}
+
+func Test_RegisterStruct(t *testing.T) {
+	t.Run("custom registry", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+
+		// --- When ---
+		err := RegisterStruct[TInner]("inner", WithRegistry(reg))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.NotNil(t, reg.Converter("inner"))
+		assert.NotNil(t, reg.Encoder("inner"))
+		assert.Equal(t, reflect.TypeFor[TInner](), reg.GoType("inner"))
+	})
+
+	t.Run("package-level registry", func(t *testing.T) {
+		// --- Given ---
+		name := t.Name()
+
+		// --- When ---
+		err := RegisterStruct[TInner](name)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.NotNil(t, registry.Converter(name))
+		assert.NotNil(t, registry.Encoder(name))
+	})
+
+	t.Run("error - not a struct", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+
+		// --- When ---
+		err := RegisterStruct[int]("int", WithRegistry(reg))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "RegisterStruct: invalid type: expected struct got int"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, reg.Converter("int"))
+	})
+
+	t.Run("error - embedded pointer", func(t *testing.T) {
+		// --- Given ---
+		type T struct {
+			*TInner
+		}
+		reg := NewRegistry()
+
+		// --- When ---
+		err := RegisterStruct[T]("t", WithRegistry(reg))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		assert.Nil(t, reg.Converter("t"))
+	})
+
+	t.Run("error - strict register conflict", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry(WithStrictRegister())
+		must.Nil(RegisterStruct[TCircle]("shape", WithRegistry(reg)))
+		enc := reg.Encoder("shape")
+
+		// --- When ---
+		err := RegisterStruct[TSquare]("shape", WithRegistry(reg))
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrConflict, err)
+		wMsg := "RegisterStruct: type name already registered: shape"
+		assert.ErrorContain(t, wMsg, err)
+		assert.Equal(t, reflect.TypeFor[TCircle](), reg.GoType("shape"))
+		assert.Same(t, enc, reg.Encoder("shape"))
+	})
+}
+
+func Test_structFields(t *testing.T) {
+	t.Run("tags", func(t *testing.T) {
+		// --- When ---
+		have, err := structFields(reflect.TypeFor[TOuter]())
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Len(t, 6, have)
+		assert.Equal(t, "id", have[0].name)
+		assert.Equal(t, []int{0}, have[0].index)
+		assert.Equal(t, reflect.TypeFor[uint64](), have[0].typ)
+		assert.False(t, have[0].omitEmpty)
+		assert.Equal(t, "inners", have[3].name)
+		assert.True(t, have[3].omitEmpty)
+		assert.Equal(t, "any", have[5].name)
+	})
+
+	t.Run("embedded", func(t *testing.T) {
+		// --- When ---
+		have, err := structFields(reflect.TypeFor[TEmbedded]())
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Len(t, 3, have)
+		assert.Equal(t, "at", have[0].name)
+		assert.Equal(t, []int{0, 0}, have[0].index)
+		assert.Equal(t, "ttl", have[1].name)
+		assert.Equal(t, "Other", have[2].name)
+	})
+
+	t.Run("embedded with tag name", func(t *testing.T) {
+		// --- Given ---
+		type T struct {
+			TInner `json:"inner"`
+		}
+
+		// --- When ---
+		have, err := structFields(reflect.TypeFor[T]())
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Len(t, 1, have)
+		assert.Equal(t, "inner", have[0].name)
+		assert.Equal(t, reflect.TypeFor[TInner](), have[0].typ)
+	})
+
+	t.Run("embedded pointer with tag name", func(t *testing.T) {
+		// --- Given ---
+		type T struct {
+			*TInner `json:"inner"`
+		}
+
+		// --- When ---
+		have, err := structFields(reflect.TypeFor[T]())
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Len(t, 1, have)
+		assert.Equal(t, reflect.TypeFor[*TInner](), have[0].typ)
+	})
+
+	t.Run("error - embedded pointer", func(t *testing.T) {
+		// --- Given ---
+		type T struct {
+			*TInner
+			Other int
+		}
+
+		// --- When ---
+		have, err := structFields(reflect.TypeFor[T]())
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		wMsg := "unsupported type: embedded pointer *jsontype.TInner"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_isStruct(t *testing.T) {
+	assert.True(t, isStruct(reflect.TypeFor[TInner]()))
+	assert.True(t, isStruct(reflect.TypeFor[*TInner]()))
+	assert.False(t, isStruct(reflect.TypeFor[int]()))
+	assert.False(t, isStruct(reflect.TypeFor[*int]()))
+}
+
+func Test_promoted(t *testing.T) {
+	t.Run("top level field", func(t *testing.T) {
+		// --- When ---
+		have := promoted(reflect.TypeFor[TEmbedded](), []int{1})
+
+		// --- Then ---
+		assert.True(t, have)
+	})
+
+	t.Run("embedded struct field", func(t *testing.T) {
+		// --- When ---
+		have := promoted(reflect.TypeFor[TEmbedded](), []int{0, 1})
+
+		// --- Then ---
+		assert.True(t, have)
+	})
+
+	t.Run("embedded struct with tag name", func(t *testing.T) {
+		// --- Given ---
+		type T struct {
+			TInner `json:"inner"`
+		}
+
+		// --- When ---
+		have := promoted(reflect.TypeFor[T](), []int{0, 1})
+
+		// --- Then ---
+		assert.False(t, have)
+	})
+
+	t.Run("embedded pointer", func(t *testing.T) {
+		// --- Given ---
+		type T struct {
+			*TInner
+		}
+
+		// --- When ---
+		have := promoted(reflect.TypeFor[T](), []int{0, 1})
+
+		// --- Then ---
+		assert.False(t, have)
+	})
+}
+
+func Test_structConverter(t *testing.T) {
+	tim := time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		reg := structRegistry(t)
+		cnv := reg.Converter("outer")
+		src := map[string]any{
+			"id":     json.Number("18446744073709551615"),
+			"name":   "abc",
+			"inner":  map[string]any{"at": "2000-01-02T03:04:05Z"},
+			"inners": []any{map[string]any{"ttl": "1s"}},
+			"counts": map[string]any{"A": json.Number("1")},
+			"any":    json.Number("42"),
+		}
+
+		// --- When ---
+		have, err := cnv(src)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := TOuter{
+			ID:     math.MaxUint64,
+			Name:   "abc",
+			Inner:  TInner{At: tim},
+			Inners: []TInner{{TTL: time.Second}},
+			Counts: map[string]uint64{"A": 1},
+			Any:    42.0,
+		}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("embedded", func(t *testing.T) {
+		// --- Given ---
+		reg := structRegistry(t)
+		cnv := reg.Converter("embedded")
+		src := map[string]any{
+			"at":    "2000-01-02T03:04:05Z",
+			"Other": json.Number("42"),
+		}
+
+		// --- When ---
+		have, err := cnv(src)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := TEmbedded{TInner: TInner{At: tim}, Other: 42}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		// --- Given ---
+		cnv := structRegistry(t).Converter("inner")
+
+		// --- When ---
+		have, err := cnv(nil)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, TInner{}, have)
+	})
+
+	t.Run("already of the struct type", func(t *testing.T) {
+		// --- Given ---
+		cnv := structRegistry(t).Converter("inner")
+
+		// --- When ---
+		have, err := cnv(TInner{TTL: time.Second})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, TInner{TTL: time.Second}, have)
+	})
+
+	t.Run("null fields", func(t *testing.T) {
+		// --- Given ---
+		cnv := structRegistry(t).Converter("outer")
+		src := map[string]any{"id": nil, "inner": nil, "counts": nil}
+
+		// --- When ---
+		have, err := cnv(src)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, TOuter{}, have)
+	})
+
+	t.Run("field of not supported composite type", func(t *testing.T) {
+		// --- Given ---
+		type T struct {
+			Buf [8192]byte `json:"buf"`
+		}
+		reg := DefaultRegistry()
+		must.Nil(RegisterStruct[T]("t", WithRegistry(reg)))
+		src := map[string]any{"buf": []any{json.Number("1")}}
+
+		// --- When ---
+		have, err := reg.Converter("t")(src)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, byte(1), have.(T).Buf[0])
+	})
+
+	t.Run("error - field of not supported map type", func(t *testing.T) {
+		// --- Given ---
+		type T struct {
+			Flags map[bool]int `json:"flags"`
+		}
+		reg := DefaultRegistry()
+		must.Nil(RegisterStruct[T]("t", WithRegistry(reg)))
+		src := map[string]any{
+			"flags": map[string]any{"true": json.Number("1")},
+		}
+
+		// --- When ---
+		have, err := reg.Converter("t")(src)
+
+		// --- Then ---
+		assert.ErrorContain(t, "field flags: json: cannot unmarshal", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("unknown fields are ignored", func(t *testing.T) {
+		// --- Given ---
+		cnv := structRegistry(t).Converter("inner")
+
+		// --- When ---
+		have, err := cnv(map[string]any{"ttl": "1s", "other": 1})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, TInner{TTL: time.Second}, have)
+	})
+
+	t.Run("strictness is fixed at registration", func(t *testing.T) {
+		// --- Given ---
+		reg := structRegistry(t)
+		data := `{"type": "inner", "value": {"ttl": "1s", "other": 1}}`
+		opts := []Option{WithRegistry(reg), WithStrict()}
+
+		// --- When ---
+		have, err := UnmarshalValue([]byte(data), opts...)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, TInner{TTL: time.Second}, have.val)
+	})
+
+	t.Run("error - strict unknown field", func(t *testing.T) {
+		// --- Given ---
+		cnv := structRegistry(t, WithStrict()).Converter("inner")
+		src := map[string]any{"ttl": "1s", "other": 1, "another": 2}
+
+		// --- When ---
+		have, err := cnv(src)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvFormat, err)
+		assert.ErrorEqual(t, `invalid format: unknown field "another"`, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - not an object", func(t *testing.T) {
+		// --- Given ---
+		cnv := structRegistry(t).Converter("inner")
+
+		// --- When ---
+		have, err := cnv([]any{})
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "invalid type: expected jsontype.TInner got []interface {}"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - registered field", func(t *testing.T) {
+		// --- Given ---
+		cnv := structRegistry(t).Converter("outer")
+		src := map[string]any{"id": json.Number("-1")}
+
+		// --- When ---
+		have, err := cnv(src)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvRange, err)
+		wMsg := "field id: value out of range: from int64 to uint64"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - nested field", func(t *testing.T) {
+		// --- Given ---
+		cnv := structRegistry(t).Converter("outer")
+		src := map[string]any{"inner": map[string]any{"ttl": "abc"}}
+
+		// --- When ---
+		have, err := cnv(src)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvValue, err)
+		wMsg := "field inner: field ttl: invalid value: " +
+			"from string to time.Duration"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - not registered field", func(t *testing.T) {
+		// --- Given ---
+		type T struct {
+			Ch chan int
+		}
+		reg := NewRegistry()
+		must.Nil(RegisterStruct[T]("t", WithRegistry(reg)))
+		cnv := reg.Converter("t")
+
+		// --- When ---
+		have, err := cnv(map[string]any{"Ch": json.Number("1")})
+
+		// --- Then ---
+		assert.ErrorContain(t, "field Ch: json: cannot unmarshal", err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_structEncoder(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		enc := structRegistry(t).Encoder("outer")
+		val := TOuter{
+			ID:     42,
+			Inner:  TInner{TTL: time.Second},
+			Inners: []TInner{{}},
+		}
+
+		// --- When ---
+		have, err := enc(val)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := map[string]any{
+			"id":   uint64(42),
+			"name": "",
+			"inner": map[string]any{
+				"at":  "0001-01-01T00:00:00Z",
+				"ttl": "1s",
+			},
+			"inners": []any{
+				map[string]any{"at": "0001-01-01T00:00:00Z"},
+			},
+		}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("error - invalid type", func(t *testing.T) {
+		// --- Given ---
+		enc := structRegistry(t).Encoder("outer")
+
+		// --- When ---
+		have, err := enc(TInner{})
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "invalid type: expected jsontype.TOuter got jsontype.TInner"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_isEmptyValue(t *testing.T) {
+	assert.True(t, isEmptyValue(reflect.ValueOf("")))
+	assert.True(t, isEmptyValue(reflect.ValueOf(0)))
+	assert.True(t, isEmptyValue(reflect.ValueOf([]int{})))
+	assert.True(t, isEmptyValue(reflect.ValueOf((*int)(nil))))
+	assert.False(t, isEmptyValue(reflect.ValueOf("a")))
+	assert.False(t, isEmptyValue(reflect.ValueOf(1)))
+	assert.False(t, isEmptyValue(reflect.ValueOf(TInner{})))
+}
+
+func Test_Registry_typeName(t *testing.T) {
+	t.Run("Go type name", func(t *testing.T) {
+		// --- When ---
+		have := DefaultRegistry().typeName(reflect.TypeFor[uint64]())
+
+		// --- Then ---
+		assert.Equal(t, "uint64", have)
+	})
+
+	t.Run("registered under different name", func(t *testing.T) {
+		// --- Given ---
+		reg := structRegistry(t)
+
+		// --- When ---
+		have := reg.typeName(reflect.TypeFor[TInner]())
+
+		// --- Then ---
+		assert.Equal(t, "inner", have)
+	})
+
+	t.Run("composite", func(t *testing.T) {
+		// --- Given ---
+		reg := structRegistry(t)
+		rt := reflect.TypeFor[map[string][2][]TInner]()
+
+		// --- When ---
+		have := reg.typeName(rt)
+
+		// --- Then ---
+		assert.Equal(t, "map[string][2][]inner", have)
+	})
+
+	t.Run("pointer", func(t *testing.T) {
+		// --- Given ---
+		reg := structRegistry(t)
+
+		// --- When ---
+		have := reg.typeName(reflect.TypeFor[[]*TInner]())
+
+		// --- Then ---
+		assert.Equal(t, "[]*inner", have)
+	})
+
+	t.Run("not supported composite", func(t *testing.T) {
+		// --- When ---
+		have := DefaultRegistry().typeName(reflect.TypeFor[map[bool]int]())
+
+		// --- Then ---
+		assert.Equal(t, "", have)
+	})
+
+	t.Run("not registered", func(t *testing.T) {
+		// --- When ---
+		have := DefaultRegistry().typeName(reflect.TypeFor[[]TInner]())
+
+		// --- Then ---
+		assert.Equal(t, "", have)
+	})
+}
+
+func Test_Value_round_trip_struct(t *testing.T) {
+	// --- Given ---
+	reg := structRegistry(t)
+	tim := time.Date(2000, 1, 2, 3, 4, 5, 600000000, time.UTC)
+	val := &Value{
+		typ: "outer",
+		val: TOuter{
+			ID:     math.MaxUint64,
+			Name:   "abc",
+			Inner:  TInner{At: tim, TTL: time.Minute},
+			Inners: []TInner{{TTL: time.Second}},
+			Counts: map[string]uint64{"A": math.MaxUint64},
+		},
+	}
+	data := must.Value(Marshal(reg, val))
+	have := &Value{}
+
+	// --- When ---
+	err := Unmarshal(reg, data, have)
+
+	// --- Then ---
+	assert.NoError(t, err)
+	assert.Equal(t, val.typ, have.typ)
+	assert.Equal(t, val.val, have.val)
+}
+
+func Test_Value_round_trip_struct_pointer_fields(t *testing.T) {
+	// --- Given ---
+	type T struct {
+		Inner *TInner        `json:"inner"`
+		Nil   *TInner        `json:"nil"`
+		TTL   *time.Duration `json:"ttl"`
+	}
+	reg := structRegistry(t)
+	must.Nil(RegisterStruct[T]("t", WithRegistry(reg)))
+	val := &Value{
+		typ: "t",
+		val: T{Inner: &TInner{TTL: time.Second}, TTL: ptr(time.Minute)},
+	}
+	data := must.Value(Marshal(reg, val))
+	have := &Value{}
+
+	// --- When ---
+	err := Unmarshal(reg, data, have)
+
+	// --- Then ---
+	assert.NoError(t, err)
+	assert.Equal(t, val.val, have.val)
+}
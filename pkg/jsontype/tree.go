@@ -0,0 +1,263 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/ctx42/convert/pkg/convert"
+)
+
+// typMap is the type name used to mark maps which would be otherwise decoded
+// as values in the format returned by [Value.Map].
+const typMap = "jsontype.Map"
+
+// Map represents a JSON object with values of any type. When marshaled, all
+// values which are not native JSON types (string, bool, float64 and nil) are
+// encoded in the same format as [Value], so they can be restored to their Go
+// types. Nested map[string]any and []any values are walked recursively.
+type Map map[string]any
+
+// MarshalJSON uses the package-level registry. To marshal with a custom
+// registry, call [MarshalMap] directly.
+func (m Map) MarshalJSON() ([]byte, error) { return MarshalMap(registry, m) }
+
+// UnmarshalJSON uses the package-level registry. To unmarshal with a custom
+// registry, call [UnmarshalMap] directly.
+func (m *Map) UnmarshalJSON(bytes []byte) error {
+	return UnmarshalMap(registry, bytes, m)
+}
+
+// List represents a JSON array with values of any type. It is marshaled and
+// unmarshalled the same way as values of the [Map].
+type List []any
+
+// MarshalJSON uses the package-level registry. To marshal with a custom
+// registry, call [MarshalList] directly.
+func (l List) MarshalJSON() ([]byte, error) { return MarshalList(registry, l) }
+
+// UnmarshalJSON uses the package-level registry. To unmarshal with a custom
+// registry, call [UnmarshalList] directly.
+func (l *List) UnmarshalJSON(bytes []byte) error {
+	return UnmarshalList(registry, bytes, l)
+}
+
+// MarshalMap marshals the [Map] using encoders from [Registry].
+func MarshalMap(reg *Registry, m Map) ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+	v, err := encodeMap(reg, m)
+	if err != nil {
+		return nil, fmt.Errorf("jsontype: %w", err)
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalMap unmarshals JSON object to the [Map] using [Registry]. Use
+// [WithAllowedTypes] or [WithTypeFilter] to limit the accepted types, the
+// other options are ignored.
+func UnmarshalMap(reg *Registry, bytes []byte, m *Map, opts ...Option) error {
+	v, err := unmarshalTree(reg, bytes, opts)
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		*m = nil
+		return nil
+	}
+	src, ok := v.(map[string]any)
+	if !ok {
+		return fmt.Errorf("jsontype: %w", invTypeError(typAnyMap, v))
+	}
+	*m = src
+	return nil
+}
+
+// MarshalList marshals the [List] using encoders from [Registry].
+func MarshalList(reg *Registry, l List) ([]byte, error) {
+	if l == nil {
+		return []byte("null"), nil
+	}
+	v, err := encodeList(reg, l)
+	if err != nil {
+		return nil, fmt.Errorf("jsontype: %w", err)
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalList unmarshals JSON array to the [List] using [Registry]. Use
+// [WithAllowedTypes] or [WithTypeFilter] to limit the accepted types, the
+// other options are ignored.
+func UnmarshalList(reg *Registry, bytes []byte, l *List, opts ...Option) error {
+	v, err := unmarshalTree(reg, bytes, opts)
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		*l = nil
+		return nil
+	}
+	src, ok := v.([]any)
+	if !ok {
+		return fmt.Errorf("jsontype: %w", invTypeError(typAnySlice, v))
+	}
+	*l = src
+	return nil
+}
+
+// unmarshalTree unmarshals JSON and decodes the tree.
+func unmarshalTree(reg *Registry, bytes []byte, opts []Option) (any, error) {
+	def := newOptions(opts...)
+	def.reg = reg
+	v, err := decodeNumber(bytes)
+	if err != nil {
+		return nil, fmt.Errorf("jsontype: %w", err)
+	}
+	if v, err = decodeTree(def, v); err != nil {
+		return nil, fmt.Errorf("jsontype: %w", err)
+	}
+	return v, nil
+}
+
+// encodeTree encodes the value for [json.Marshal]. Maps and slices are
+// walked, native JSON values are returned as they are, and all other values
+// are encoded in the [Value.Map] format.
+func encodeTree(reg *Registry, v any) (any, error) {
+	switch val := v.(type) {
+	case nil, string, bool, float64, json.Number:
+		return val, nil
+	case Map:
+		return encodeMap(reg, val)
+	case map[string]any:
+		return encodeMap(reg, val)
+	case List:
+		return encodeList(reg, val)
+	case []any:
+		return encodeList(reg, val)
+	case *Value:
+		return encodeLeaf(reg, val)
+	default:
+		rt := reflect.TypeOf(v)
+		typ := reg.typeName(rt)
+		if typ == "" {
+			return nil, fmt.Errorf("%w: %s", convert.ErrUnsType, rt)
+		}
+		return encodeLeaf(reg, &Value{typ: typ, val: v})
+	}
+}
+
+// encodeMap encodes map values. Maps which have the same structure as the map
+// returned by [Value.Map] are marked, so they are not mistaken for values
+// when decoded.
+func encodeMap(reg *Registry, m map[string]any) (any, error) {
+	if m == nil {
+		return nil, nil
+	}
+	dst := make(map[string]any, len(m))
+	for key, elem := range m {
+		v, err := encodeTree(reg, elem)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", key, err)
+		}
+		dst[key] = v
+	}
+	if isValueMap(m) {
+		return map[string]any{"type": typMap, "value": dst}, nil
+	}
+	return dst, nil
+}
+
+// encodeList encodes slice values.
+func encodeList(reg *Registry, l []any) (any, error) {
+	if l == nil {
+		return nil, nil
+	}
+	dst := make([]any, len(l))
+	for i, elem := range l {
+		var err error
+		if dst[i], err = encodeTree(reg, elem); err != nil {
+			return nil, fmt.Errorf("index %d: %w", i, err)
+		}
+	}
+	return dst, nil
+}
+
+// encodeLeaf encodes the value in the [Value.Map] format.
+func encodeLeaf(reg *Registry, val *Value) (any, error) {
+	v, err := val.encode(reg)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"type": val.typ, "value": v}, nil
+}
+
+// decodeTree decodes the value decoded from JSON by [decodeNumber]. Maps in
+// the [Value.Map] format are converted with converters from the registry,
+// other maps and slices are walked, and numbers are converted to float64.
+// Returns an error when a type name is rejected by the type filter.
+func decodeTree(def *Options, v any) (any, error) {
+	switch val := v.(type) {
+	case json.Number:
+		return parseFloat(val)
+
+	case []any:
+		for i, elem := range val {
+			var err error
+			if val[i], err = decodeTree(def, elem); err != nil {
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+		return val, nil
+
+	case map[string]any:
+		if !isValueMap(val) {
+			return decodeMap(def, val)
+		}
+		typ, _ := val["type"].(string)
+		if typ == typMap {
+			m, ok := val["value"].(map[string]any)
+			if !ok {
+				return nil, invTypeError(typAnyMap, val["value"])
+			}
+			return decodeMap(def, m)
+		}
+		if err := def.allowed(typ); err != nil {
+			return nil, err
+		}
+		cnv := def.reg.decoder(typ)
+		if cnv == nil {
+			return nil, fmt.Errorf("%w: %s", convert.ErrUnsType, typ)
+		}
+		return cnv(val["value"])
+
+	default:
+		return val, nil
+	}
+}
+
+// decodeMap decodes map values.
+func decodeMap(def *Options, m map[string]any) (any, error) {
+	for key, elem := range m {
+		v, err := decodeTree(def, elem)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", key, err)
+		}
+		m[key] = v
+	}
+	return m, nil
+}
+
+// isValueMap returns true if the map has the same structure as the map
+// returned by [Value.Map].
+func isValueMap(m map[string]any) bool {
+	if len(m) != 2 {
+		return false
+	}
+	_, hasValue := m["value"]
+	_, isString := m["type"].(string)
+	return hasValue && isString
+}
@@ -0,0 +1,398 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ctx42/convert/pkg/convert"
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
+)
+
+func Test_Map_MarshalJSON(t *testing.T) {
+	t.Run("nested", func(t *testing.T) {
+		// --- Given ---
+		m := Map{
+			"nil":  nil,
+			"str":  "abc",
+			"f64":  1.5,
+			"u64":  uint64(1<<64 - 1),
+			"list": []any{true, time.Second},
+			"map":  map[string]any{"i8": int8(-8)},
+			"val":  New(uint(1)),
+		}
+
+		// --- When ---
+		have, err := json.Marshal(m)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := `{
+			"f64": 1.5,
+			"list": [true, {"type": "time.Duration", "value": "1s"}],
+			"map": {"i8": {"type": "int8", "value": -8}},
+			"nil": null,
+			"str": "abc",
+			"u64": {"type": "uint64", "value": 18446744073709551615},
+			"val": {"type": "uint", "value": 1}
+		}`
+		assert.JSON(t, want, string(have))
+	})
+
+	t.Run("map looking like a value", func(t *testing.T) {
+		// --- Given ---
+		m := Map{"type": "uint", "value": 1.0}
+
+		// --- When ---
+		have, err := json.Marshal(m)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := `{
+			"type": "jsontype.Map",
+			"value": {"type": "uint", "value": 1}
+		}`
+		assert.JSON(t, want, string(have))
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		// --- Given ---
+		var m Map
+
+		// --- When ---
+		have, err := json.Marshal(m)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "null", string(have))
+	})
+
+	t.Run("error - unsupported type", func(t *testing.T) {
+		// --- Given ---
+		m := Map{"list": []any{struct{}{}}}
+
+		// --- When ---
+		have, err := json.Marshal(m)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		wMsg := "jsontype: key \"list\": index 0: unsupported type: struct {}"
+		assert.ErrorContain(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_Map_UnmarshalJSON(t *testing.T) {
+	t.Run("nested", func(t *testing.T) {
+		// --- Given ---
+		data := `{
+			"f64": 1.5,
+			"list": [true, {"type": "time.Duration", "value": "1s"}],
+			"map": {"i8": {"type": "int8", "value": -8}},
+			"nil": null,
+			"str": "abc",
+			"u64": {"type": "uint64", "value": 18446744073709551615},
+			"u64s": {"type": "[]uint64", "value": [1, 2]}
+		}`
+
+		// --- When ---
+		var have Map
+		err := json.Unmarshal([]byte(data), &have)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := Map{
+			"f64":  1.5,
+			"list": []any{true, time.Second},
+			"map":  map[string]any{"i8": int8(-8)},
+			"nil":  nil,
+			"str":  "abc",
+			"u64":  uint64(1<<64 - 1),
+			"u64s": []uint64{1, 2},
+		}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("map looking like a value", func(t *testing.T) {
+		// --- Given ---
+		data := `{
+			"type": "jsontype.Map",
+			"value": {"type": "uint", "value": 1}
+		}`
+
+		// --- When ---
+		var have Map
+		err := json.Unmarshal([]byte(data), &have)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, Map{"type": "uint", "value": 1.0}, have)
+	})
+
+	t.Run("null", func(t *testing.T) {
+		// --- Given ---
+		have := Map{"a": 1.0}
+
+		// --- When ---
+		err := json.Unmarshal([]byte(`null`), &have)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		// --- Given ---
+		tim := time.Date(2000, 1, 2, 3, 4, 5, 6, time.UTC)
+		m := Map{
+			"time": tim,
+			"list": []any{uint8(1), []any{int64(-1 << 63)}},
+			"map":  map[string]any{"type": "abc", "value": ptr(uint16(2))},
+		}
+		data := must.Value(json.Marshal(m))
+
+		// --- When ---
+		var have Map
+		err := json.Unmarshal(data, &have)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, m, have)
+	})
+
+	t.Run("error - unsupported type", func(t *testing.T) {
+		// --- Given ---
+		data := `{"a": [{"type": "unknown", "value": 1}]}`
+
+		// --- When ---
+		var have Map
+		err := json.Unmarshal([]byte(data), &have)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		wMsg := "jsontype: key \"a\": index 0: unsupported type: unknown"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - conversion", func(t *testing.T) {
+		// --- Given ---
+		data := `{"a": {"type": "uint8", "value": 256}}`
+
+		// --- When ---
+		var have Map
+		err := json.Unmarshal([]byte(data), &have)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvRange, err)
+		assert.ErrorContain(t, "jsontype: key \"a\": ", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - invalid escaped map", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "jsontype.Map", "value": 1}`
+
+		// --- When ---
+		var have Map
+		err := json.Unmarshal([]byte(data), &have)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "jsontype: invalid type: expected map[string]interface {} " +
+			"got json.Number"
+		assert.ErrorEqual(t, wMsg, err)
+	})
+
+	t.Run("error - not an object", func(t *testing.T) {
+		// --- When ---
+		var have Map
+		err := json.Unmarshal([]byte(`[]`), &have)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "jsontype: invalid type: expected map[string]interface {} " +
+			"got []interface {}"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_MarshalMap(t *testing.T) {
+	t.Run("custom registry", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+		m := Map{"circle": TCircle{R: 1.5}}
+
+		// --- When ---
+		have, err := MarshalMap(reg, m)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := `{"circle": {"type": "circle", "value": {"r": 1.5}}}`
+		assert.JSON(t, want, string(have))
+	})
+}
+
+func Test_UnmarshalMap(t *testing.T) {
+	t.Run("custom registry", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+		data := `{"circle": {"type": "circle", "value": {"r": 1.5}}}`
+
+		// --- When ---
+		var have Map
+		err := UnmarshalMap(reg, []byte(data), &have)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, Map{"circle": TCircle{R: 1.5}}, have)
+	})
+
+	t.Run("error - type not allowed", func(t *testing.T) {
+		// --- Given ---
+		data := `{"a": [{"type": "uint", "value": 1}]}`
+		opt := WithAllowedTypes(Int)
+
+		// --- When ---
+		var have Map
+		err := UnmarshalMap(registry, []byte(data), &have, opt)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrTypeNotAllowed, err)
+		wMsg := `jsontype: key "a": index 0: type not allowed: uint`
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - invalid JSON", func(t *testing.T) {
+		// --- When ---
+		var have Map
+		err := UnmarshalMap(registry, []byte(`{!}`), &have)
+
+		// --- Then ---
+		assert.ErrorContain(t, "jsontype: invalid character", err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_List_MarshalJSON(t *testing.T) {
+	t.Run("nested", func(t *testing.T) {
+		// --- Given ---
+		l := List{"abc", uint(1), List{int16(2)}, Map{"a": nil}}
+
+		// --- When ---
+		have, err := json.Marshal(l)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := `[
+			"abc",
+			{"type": "uint", "value": 1},
+			[{"type": "int16", "value": 2}],
+			{"a": null}
+		]`
+		assert.JSON(t, want, string(have))
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		// --- Given ---
+		var l List
+
+		// --- When ---
+		have, err := json.Marshal(l)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "null", string(have))
+	})
+
+	t.Run("error - unsupported type", func(t *testing.T) {
+		// --- Given ---
+		l := List{make(chan int)}
+
+		// --- When ---
+		have, err := json.Marshal(l)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+		assert.ErrorContain(t, "jsontype: index 0: unsupported type", err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_List_UnmarshalJSON(t *testing.T) {
+	t.Run("nested", func(t *testing.T) {
+		// --- Given ---
+		data := `[
+			"abc",
+			1,
+			{"type": "uint", "value": 1},
+			[{"type": "int16", "value": 2}],
+			{"a": null}
+		]`
+
+		// --- When ---
+		var have List
+		err := json.Unmarshal([]byte(data), &have)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := List{
+			"abc",
+			1.0,
+			uint(1),
+			[]any{int16(2)},
+			map[string]any{"a": nil},
+		}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("error - not an array", func(t *testing.T) {
+		// --- When ---
+		var have List
+		err := json.Unmarshal([]byte(`{}`), &have)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "jsontype: invalid type: expected []interface {} " +
+			"got map[string]interface {}"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_UnmarshalList(t *testing.T) {
+	t.Run("custom registry", func(t *testing.T) {
+		// --- Given ---
+		reg := shapeRegistry(t)
+		data := `[{"type": "circle", "value": {"r": 1.5}}]`
+
+		// --- When ---
+		var have List
+		err := UnmarshalList(reg, []byte(data), &have)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, List{TCircle{R: 1.5}}, have)
+	})
+
+	t.Run("error - type not allowed", func(t *testing.T) {
+		// --- Given ---
+		data := `[{"type": "int", "value": 1}, {"type": "uint", "value": 1}]`
+		opt := WithAllowedTypes(Int)
+
+		// --- When ---
+		var have List
+		err := UnmarshalList(registry, []byte(data), &have, opt)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrTypeNotAllowed, err)
+		assert.ErrorEqual(t, "jsontype: index 1: type not allowed: uint", err)
+		assert.Nil(t, have)
+	})
+}
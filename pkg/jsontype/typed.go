@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/ctx42/convert/pkg/convert"
+)
+
+// Typed represents a value of type T. It is marshaled to the same JSON format
+// as [Value], but when unmarshaled, it requires the type name to match T.
+//
+// It is meant to be used as a struct field when the field's Go type is known
+// at compile time, but the JSON format must still carry the type name.
+type Typed[T any] struct {
+	val T // The value to encode.
+}
+
+// NewTyped returns new instance of [Typed] for the given value.
+func NewTyped[T any](value T) Typed[T] { return Typed[T]{val: value} }
+
+// GoTypeName returns the Go type name of T.
+func (typ Typed[T]) GoTypeName() string { return reflect.TypeFor[T]().String() }
+
+// Get returns the underlying value.
+func (typ Typed[T]) Get() T { return typ.val }
+
+// Set sets the underlying value.
+func (typ *Typed[T]) Set(value T) { typ.val = value }
+
+// Value returns the [Value] representation.
+func (typ Typed[T]) Value() *Value { return New(typ.val) }
+
+func (typ Typed[T]) MarshalJSON() ([]byte, error) {
+	return typ.Value().MarshalJSON()
+}
+
+// UnmarshalJSON uses the package-level registry. To unmarshal with a custom
+// registry, call [UnmarshalTyped] directly.
+func (typ *Typed[T]) UnmarshalJSON(bytes []byte) error {
+	return UnmarshalTyped(registry, bytes, typ)
+}
+
+// UnmarshalTyped unmarshals JSON representation of the value using
+// [Registry]. Returns an error when the type name in JSON doesn't match T, the
+// value is not converted then.
+func UnmarshalTyped[T any](reg *Registry, bytes []byte, typ *Typed[T]) error {
+	tmp := rawValue{}
+	if err := json.Unmarshal(bytes, &tmp); err != nil {
+		return fmt.Errorf("jsontype: %w", err)
+	}
+	if want := typ.GoTypeName(); tmp.Type != want {
+		format := "jsontype: types do not match: expected %s got %s: %w"
+		return fmt.Errorf(format, want, tmp.Type, convert.ErrInvType)
+	}
+	cnv := reg.decoder(tmp.Type)
+	if cnv == nil {
+		return fmt.Errorf("%w: %s", convert.ErrUnsType, tmp.Type)
+	}
+	val, err := decodeValue(cnv, tmp.Value)
+	if err != nil {
+		return err
+	}
+	if val == nil {
+		var zero T
+		typ.val = zero
+		return nil
+	}
+	v, ok := val.(T)
+	if !ok {
+		format := "jsontype: expected %s got %T: %w"
+		return fmt.Errorf(format, typ.GoTypeName(), val, convert.ErrInvType)
+	}
+	typ.val = v
+	return nil
+}
@@ -0,0 +1,273 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ctx42/convert/pkg/convert"
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
+)
+
+func Test_NewTyped(t *testing.T) {
+	// --- When ---
+	have := NewTyped(uint64(42))
+
+	// --- Then ---
+	assert.Equal(t, uint64(42), have.val)
+}
+
+func Test_Typed_GoTypeName(t *testing.T) {
+	// --- Given ---
+	typ := NewTyped([]time.Time{})
+
+	// --- When ---
+	have := typ.GoTypeName()
+
+	// --- Then ---
+	assert.Equal(t, "[]time.Time", have)
+}
+
+func Test_Typed_Get(t *testing.T) {
+	// --- Given ---
+	typ := NewTyped(uint8(42))
+
+	// --- When ---
+	have := typ.Get()
+
+	// --- Then ---
+	assert.Equal(t, uint8(42), have)
+}
+
+func Test_Typed_Set(t *testing.T) {
+	// --- Given ---
+	typ := NewTyped(uint8(42))
+
+	// --- When ---
+	typ.Set(44)
+
+	// --- Then ---
+	assert.Equal(t, uint8(44), typ.val)
+}
+
+func Test_Typed_Value(t *testing.T) {
+	// --- Given ---
+	typ := NewTyped(uint8(42))
+
+	// --- When ---
+	have := typ.Value()
+
+	// --- Then ---
+	assert.Equal(t, Uint8, have.typ)
+	assert.Equal(t, uint8(42), have.val)
+}
+
+func Test_Typed_MarshalJSON(t *testing.T) {
+	t.Run("value", func(t *testing.T) {
+		// --- Given ---
+		typ := NewTyped(uint64(math.MaxUint64))
+
+		// --- When ---
+		have, err := typ.MarshalJSON()
+
+		// --- Then ---
+		assert.NoError(t, err)
+		wJSON := `{"type":"uint64","value":18446744073709551615}`
+		assert.JSON(t, wJSON, string(have))
+	})
+
+	t.Run("struct field", func(t *testing.T) {
+		// --- Given ---
+		v := struct {
+			ID Typed[uint64] `json:"id"`
+		}{ID: NewTyped(uint64(42))}
+
+		// --- When ---
+		have, err := json.Marshal(v)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.JSON(t, `{"id":{"type":"uint64","value":42}}`, string(have))
+	})
+}
+
+func Test_Typed_UnmarshalJSON(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "uint64", "value": 18446744073709551615}`
+		typ := &Typed[uint64]{}
+
+		// --- When ---
+		err := typ.UnmarshalJSON([]byte(data))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(math.MaxUint64), typ.val)
+	})
+
+	t.Run("struct field", func(t *testing.T) {
+		// --- Given ---
+		data := `{"id": {"type": "[]uint16", "value": [1, 2]}}`
+		v := struct {
+			ID Typed[[]uint16] `json:"id"`
+		}{}
+
+		// --- When ---
+		err := json.Unmarshal([]byte(data), &v)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []uint16{1, 2}, v.ID.Get())
+	})
+
+	t.Run("error - types do not match", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "int", "value": 42}`
+		typ := &Typed[uint64]{}
+
+		// --- When ---
+		err := typ.UnmarshalJSON([]byte(data))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "jsontype: types do not match: expected uint64 got int: " +
+			"invalid type"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Zero(t, typ.val)
+	})
+
+	t.Run("error - unsupported type", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "complex64", "value": 42}`
+		typ := &Typed[complex64]{}
+
+		// --- When ---
+		err := typ.UnmarshalJSON([]byte(data))
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrUnsType, err)
+	})
+}
+
+func Test_UnmarshalTyped(t *testing.T) {
+	type MyType int
+
+	t.Run("custom registry", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+		reg.Register("jsontype.MyType", func(v any) (any, error) {
+			return MyType(44), nil
+		})
+		data := `{"type": "jsontype.MyType", "value": 42}`
+		typ := &Typed[MyType]{}
+
+		// --- When ---
+		err := UnmarshalTyped(reg, []byte(data), typ)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, MyType(44), typ.val)
+	})
+
+	t.Run("error - converter returns other type", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+		reg.Register("jsontype.MyType", func(v any) (any, error) {
+			return "abc", nil
+		})
+		data := `{"type": "jsontype.MyType", "value": 42}`
+		typ := &Typed[MyType]{}
+
+		// --- When ---
+		err := UnmarshalTyped(reg, []byte(data), typ)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "jsontype: expected jsontype.MyType got string: invalid type"
+		assert.ErrorEqual(t, wMsg, err)
+	})
+
+	t.Run("error - types do not match", func(t *testing.T) {
+		// --- Given ---
+		var called bool
+		reg := NewRegistry()
+		reg.Register("other", func(v any) (any, error) {
+			called = true
+			return MyType(44), nil
+		})
+		data := `{"type": "other", "value": 42}`
+		typ := &Typed[MyType]{}
+
+		// --- When ---
+		err := UnmarshalTyped(reg, []byte(data), typ)
+
+		// --- Then ---
+		assert.ErrorIs(t, convert.ErrInvType, err)
+		wMsg := "jsontype: types do not match: " +
+			"expected jsontype.MyType got other: invalid type"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.False(t, called)
+		assert.Zero(t, typ.val)
+	})
+
+	t.Run("error - invalid JSON", func(t *testing.T) {
+		// --- Given ---
+		typ := &Typed[MyType]{}
+
+		// --- When ---
+		err := UnmarshalTyped(NewRegistry(), []byte(`{!}`), typ)
+
+		// --- Then ---
+		assert.ErrorContain(t, "jsontype: invalid character", err)
+	})
+
+	t.Run("nil value", func(t *testing.T) {
+		// --- Given ---
+		reg := NewRegistry()
+		reg.Register("jsontype.MyType", NilConverter)
+		data := `{"type": "jsontype.MyType", "value": null}`
+		typ := &Typed[MyType]{val: 42}
+
+		// --- When ---
+		err := UnmarshalTyped(reg, []byte(data), typ)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, MyType(0), typ.val)
+	})
+}
+
+func Test_Typed_round_trip(t *testing.T) {
+	// --- Given ---
+	tim := time.Date(2000, 1, 2, 3, 4, 5, 600000000, time.UTC)
+	typ := NewTyped(map[string]time.Time{"A": tim})
+	data := must.Value(json.Marshal(typ))
+	have := Typed[map[string]time.Time]{}
+
+	// --- When ---
+	err := json.Unmarshal(data, &have)
+
+	// --- Then ---
+	assert.NoError(t, err)
+	assert.Equal(t, typ.Get(), have.Get())
+}
+
+func Test_Typed_round_trip_nil_pointer(t *testing.T) {
+	// --- Given ---
+	typ := NewTyped[*uint64](nil)
+	data := must.Value(json.Marshal(typ))
+	have := Typed[*uint64]{val: ptr(uint64(42))}
+
+	// --- When ---
+	err := json.Unmarshal(data, &have)
+
+	// --- Then ---
+	assert.NoError(t, err)
+	assert.JSON(t, `{"type":"*uint64","value":null}`, string(data))
+	assert.Nil(t, have.Get())
+}
@@ -0,0 +1,45 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// UnknownValue represents a value with a type name not known to the registry.
+// It keeps the raw JSON representation of the value, so it can be stored or
+// forwarded without losing any information.
+//
+// Values of unknown types are decoded only when the [WithUnknown] option is
+// used. They are returned as the Go value of [Value] with the same type name.
+type UnknownValue struct {
+	typ   string          // Type name.
+	raw   []byte          // Raw JSON of the whole value.
+	value json.RawMessage // Raw JSON of the "value" field.
+}
+
+// newUnknownValue returns new instance of [UnknownValue]. The data is copied.
+func newUnknownValue(
+	typ string,
+	raw []byte,
+	value json.RawMessage,
+) *UnknownValue {
+
+	return &UnknownValue{
+		typ:   typ,
+		raw:   bytes.Clone(raw),
+		value: value,
+	}
+}
+
+// GoTypeName returns the type name of the value.
+func (u *UnknownValue) GoTypeName() string { return u.typ }
+
+// Raw returns raw JSON of the value field.
+func (u *UnknownValue) Raw() json.RawMessage { return u.value }
+
+// MarshalJSON returns the JSON representation of the value exactly as it was
+// decoded. Note that [json.Marshal] compacts the returned JSON.
+func (u *UnknownValue) MarshalJSON() ([]byte, error) { return u.raw, nil }
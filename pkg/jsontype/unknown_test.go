@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: (c) 2026 Rafal Zajac
+// SPDX-License-Identifier: MIT
+
+package jsontype
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
+)
+
+func Test_newUnknownValue(t *testing.T) {
+	// --- Given ---
+	raw := []byte(`{"type": "money", "value": 1}`)
+
+	// --- When ---
+	have := newUnknownValue("money", raw, json.RawMessage(`1`))
+
+	// --- Then ---
+	assert.Equal(t, "money", have.typ)
+	assert.Equal(t, string(raw), string(have.raw))
+	assert.NotSame(t, &raw[0], &have.raw[0])
+	assert.Equal(t, `1`, string(have.value))
+}
+
+func Test_UnknownValue_GoTypeName(t *testing.T) {
+	// --- Given ---
+	u := newUnknownValue("money", nil, nil)
+
+	// --- When ---
+	have := u.GoTypeName()
+
+	// --- Then ---
+	assert.Equal(t, "money", have)
+}
+
+func Test_UnknownValue_Raw(t *testing.T) {
+	// --- Given ---
+	u := newUnknownValue("money", nil, json.RawMessage(`{"a": 1}`))
+
+	// --- When ---
+	have := u.Raw()
+
+	// --- Then ---
+	assert.Equal(t, `{"a": 1}`, string(have))
+}
+
+func Test_UnknownValue_MarshalJSON(t *testing.T) {
+	t.Run("verbatim", func(t *testing.T) {
+		// --- Given ---
+		data := `{ "value": {"b": 1, "a": 2.50}, "type": "money" }`
+		val := must.Value(UnmarshalValue([]byte(data), WithUnknown()))
+
+		// --- When ---
+		have, err := val.GoValue().(*UnknownValue).MarshalJSON()
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, data, string(have))
+	})
+
+	t.Run("value round trip", func(t *testing.T) {
+		// --- Given ---
+		data := `{"value":{"b":1,"a":2.50,"c":1e400},"type":"money"}`
+		val := must.Value(UnmarshalValue([]byte(data), WithUnknown()))
+
+		// --- When ---
+		have, err := json.Marshal(val)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, data, string(have))
+	})
+
+	t.Run("nested in map", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "money", "value": {"b": 1, "a": 2.50}}`
+		val := must.Value(UnmarshalValue([]byte(data), WithUnknown()))
+
+		// --- When ---
+		have, err := json.Marshal(Map{"m": val})
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := `{"m":{"type":"money","value":{"b":1,"a":2.50}}}`
+		assert.Equal(t, want, string(have))
+	})
+
+	t.Run("value map", func(t *testing.T) {
+		// --- Given ---
+		data := `{"type": "money", "value": 1.50}`
+		val := must.Value(UnmarshalValue([]byte(data), WithUnknown()))
+
+		// --- When ---
+		have := val.Map()
+
+		// --- Then ---
+		want := map[string]any{
+			"type":  "money",
+			"value": json.RawMessage(`1.50`),
+		}
+		assert.Equal(t, want, have)
+	})
+}